@@ -0,0 +1,303 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/FantLab/go-kit/crypto/signed"
+)
+
+var errUsage = errors.New("invalid usage")
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	return nil
+}
+
+// keys are written as base64 files readable by signed.NewFileCoder64 and signed.NewFileKeyring64
+func keygen(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("keygen", stderr)
+	dir := fs.String("dir", ".", "output directory")
+	name := fs.String("name", "signed", "key file name, used as the key id by keyrings")
+	force := fs.Bool("force", false, "overwrite existing key files")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	coder, err := signed.Generate()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0700); err != nil {
+		return err
+	}
+
+	publicKeyFile := filepath.Join(*dir, *name+".pub")
+	privateKeyFile := filepath.Join(*dir, *name+".key")
+
+	if !*force {
+		for _, file := range []string{publicKeyFile, privateKeyFile} {
+			if _, err := os.Stat(file); err == nil {
+				return fmt.Errorf("%s already exists, use -force to overwrite", file)
+			}
+		}
+	}
+
+	if err := writeKeyFile(privateKeyFile, coder.PrivateKey(), 0600, *force); err != nil {
+		return err
+	}
+	if err := writeKeyFile(publicKeyFile, coder.PublicKey(), 0644, *force); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, publicKeyFile)
+	fmt.Fprintln(stdout, privateKeyFile)
+
+	return nil
+}
+
+func writeKeyFile(file string, key signed.Key, perm os.FileMode, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(file, flags, perm)
+	if err != nil {
+		return err
+	}
+
+	// umask and existing files may leave the permissions wider than requested
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.WriteString(key.String()); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func sign(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("sign", stderr)
+	publicKeyFile := fs.String("pub", "signed.pub", "public key file")
+	privateKeyFile := fs.String("key", "signed.key", "private key file")
+	keyring := fs.String("keyring", "", "sign with the current key of a keyring directory")
+	in := fs.String("in", "-", "payload file, - for stdin")
+	detached := fs.String("detached", "", "write a detached signature of the payload file to this file")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *detached != "" {
+		coder, err := signed.NewFileCoder64(*publicKeyFile, *privateKeyFile)
+		if err != nil {
+			return err
+		}
+		r, err := openInput(*in, stdin)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		signature, err := coder.SignDetached(r)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(*detached, signature, 0644)
+	}
+
+	payload, err := readInput(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	var token []byte
+
+	if *keyring != "" {
+		k, err := signed.NewFileKeyring64(*keyring)
+		if err != nil {
+			return err
+		}
+		if token, err = k.Encode(payload); err != nil {
+			return err
+		}
+	} else {
+		coder, err := signed.NewFileCoder64(*publicKeyFile, *privateKeyFile)
+		if err != nil {
+			return err
+		}
+		token = coder.Encode(payload)
+	}
+
+	_, err = fmt.Fprintf(stdout, "%s\n", token)
+
+	return err
+}
+
+func verify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	publicKeyFile := fs.String("pub", "signed.pub", "public key file")
+	keyring := fs.String("keyring", "", "verify with the keys of a keyring directory")
+	in := fs.String("in", "-", "token file, or the signed file with -detached, - for stdin")
+	detached := fs.String("detached", "", "detached signature file")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *detached != "" {
+		verifier, err := signed.NewFileVerifier64(*publicKeyFile)
+		if err != nil {
+			return err
+		}
+		signature, err := ioutil.ReadFile(*detached)
+		if err != nil {
+			return err
+		}
+		r, err := openInput(*in, stdin)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		if err := verifier.VerifyDetached(r, signature); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, "OK")
+		return err
+	}
+
+	token, err := readInput(*in, stdin)
+	if err != nil {
+		return err
+	}
+
+	token = bytes.TrimSpace(token)
+
+	var payload []byte
+
+	if *keyring != "" {
+		k, err := signed.NewFileKeyring64(*keyring)
+		if err != nil {
+			return err
+		}
+		payload, err = k.Decode(token)
+		if err != nil {
+			return err
+		}
+	} else {
+		verifier, err := signed.NewFileVerifier64(*publicKeyFile)
+		if err != nil {
+			return err
+		}
+		payload, err = verifier.Decode(token)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = stdout.Write(payload)
+
+	return err
+}
+
+func inspect(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("inspect", stderr)
+	in := fs.String("in", "-", "token file")
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var token []byte
+
+	if fs.NArg() > 0 {
+		token = []byte(fs.Arg(0))
+	} else {
+		var err error
+		if token, err = readInput(*in, stdin); err != nil {
+			return err
+		}
+	}
+
+	parts := bytes.Split(bytes.TrimSpace(token), []byte{'.'})
+
+	var payload64 []byte
+
+	switch {
+	case len(parts) == 2:
+		fmt.Fprintln(stdout, "format: signed")
+		payload64 = parts[0]
+	case len(parts) == 3 && string(parts[0]) == "e1":
+		fmt.Fprintln(stdout, "format: sealed")
+		fmt.Fprintln(stdout, "payload is encrypted")
+		return nil
+	case len(parts) == 3 && isJWTHeader(parts[0]):
+		header, _ := base64.RawURLEncoding.DecodeString(string(parts[0]))
+		fmt.Fprintln(stdout, "format: jwt")
+		fmt.Fprintf(stdout, "header: %s\n", header)
+		payload64 = parts[1]
+	case len(parts) == 3:
+		fmt.Fprintln(stdout, "format: keyring")
+		fmt.Fprintf(stdout, "key id: %s\n", parts[0])
+		payload64 = parts[1]
+	default:
+		return signed.ErrInput
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(string(payload64))
+	if err != nil {
+		return signed.ErrInput
+	}
+
+	_, err = fmt.Fprintf(stdout, "payload: %s\n", payload)
+
+	return err
+}
+
+func isJWTHeader(header64 []byte) bool {
+	data, err := base64.RawURLEncoding.DecodeString(string(header64))
+	if err != nil {
+		return false
+	}
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	return json.Unmarshal(data, &header) == nil && header.Algorithm != ""
+}
+
+func openInput(file string, stdin io.Reader) (io.ReadCloser, error) {
+	if file == "-" {
+		return ioutil.NopCloser(stdin), nil
+	}
+	return os.Open(file)
+}
+
+func readInput(file string, stdin io.Reader) ([]byte, error) {
+	if file == "-" {
+		return ioutil.ReadAll(stdin)
+	}
+	return ioutil.ReadFile(file)
+}
@@ -0,0 +1,132 @@
+package signed
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+var (
+	ErrExpired       = errors.New("signed: token expired")
+	ErrNotYetValid   = errors.New("signed: token not yet valid")
+	ErrAudience      = errors.New("signed: invalid audience")
+	ErrIssuer        = errors.New("signed: invalid issuer")
+	ErrMissingExpiry = errors.New("signed: missing expiry")
+)
+
+// accepts both a single string and an array in JSON
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = Audience(list)
+	return nil
+}
+
+func (a Audience) Contains(s string) bool {
+	for _, x := range a {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+// embed into custom claims types, times are unix seconds
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+type ClaimsHolder interface {
+	StandardClaims() *Claims
+}
+
+func (c *Claims) StandardClaims() *Claims {
+	return c
+}
+
+func (c *Claims) SetLifetime(now time.Time, ttl time.Duration) {
+	c.IssuedAt = now.Unix()
+	c.NotBefore = now.Unix()
+	c.ExpiresAt = now.Add(ttl).Unix()
+}
+
+type Validator struct {
+	Audience      string
+	Issuer        string
+	Leeway        time.Duration
+	RequireExpiry bool
+	Clock         func() time.Time
+}
+
+func (v *Validator) Validate(c *Claims) error {
+	now := time.Now
+
+	if v.Clock != nil {
+		now = v.Clock
+	}
+
+	t := now()
+
+	if c.ExpiresAt != 0 {
+		if !t.Add(-v.Leeway).Before(time.Unix(c.ExpiresAt, 0)) {
+			return ErrExpired
+		}
+	} else if v.RequireExpiry {
+		return ErrMissingExpiry
+	}
+	if c.NotBefore != 0 && t.Add(v.Leeway).Before(time.Unix(c.NotBefore, 0)) {
+		return ErrNotYetValid
+	}
+	// tokens scoped to an audience are rejected by validators that don't name one
+	if v.Audience == "" && len(c.Audience) > 0 || v.Audience != "" && !c.Audience.Contains(v.Audience) {
+		return ErrAudience
+	}
+	if v.Issuer != "" && c.Issuer != v.Issuer {
+		return ErrIssuer
+	}
+	return nil
+}
+
+var defaultValidator = new(Validator)
+
+func (c *Coder) EncodeClaims(claims ClaimsHolder) ([]byte, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	return c.Encode(payload), nil
+}
+
+// v may be nil to check only the token lifetime against the current time
+func (c *Coder) DecodeClaims(input []byte, claims ClaimsHolder, v *Validator) error {
+	payload, err := c.Decode(input)
+	if err != nil {
+		return err
+	}
+	return unmarshalClaims(payload, claims, v)
+}
+
+func unmarshalClaims(payload []byte, claims ClaimsHolder, v *Validator) error {
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ErrInput
+	}
+	if v == nil {
+		v = defaultValidator
+	}
+	return v.Validate(claims.StandardClaims())
+}
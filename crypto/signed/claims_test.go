@@ -0,0 +1,103 @@
+package signed
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+type userClaims struct {
+	Claims
+	UserID int64 `json:"uid"`
+}
+
+func Test_Claims(t *testing.T) {
+	coder, _ := Generate()
+
+	now := time.Unix(1000000, 0)
+
+	claims := &userClaims{UserID: 42}
+	claims.Issuer = "fantlab"
+	claims.Audience = Audience{"api", "forum"}
+	claims.SetLifetime(now, time.Hour)
+
+	token, err := coder.EncodeClaims(claims)
+
+	assert.True(t, err == nil)
+
+	validator := func(offset time.Duration) *Validator {
+		return &Validator{
+			Audience: "forum",
+			Issuer:   "fantlab",
+			Leeway:   time.Minute,
+			Clock:    func() time.Time { return now.Add(offset) },
+		}
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		var c userClaims
+		err := coder.DecodeClaims(token, &c, validator(0))
+
+		assert.True(t, err == nil)
+		assert.DeepEqual(t, c, *claims)
+	})
+
+	t.Run("leeway", func(t *testing.T) {
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), validator(time.Hour+30*time.Second)) == nil)
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), validator(-30*time.Second)) == nil)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), validator(time.Hour+time.Minute)) == ErrExpired)
+	})
+
+	t.Run("not_yet_valid", func(t *testing.T) {
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), validator(-2*time.Minute)) == ErrNotYetValid)
+	})
+
+	t.Run("audience", func(t *testing.T) {
+		v := validator(0)
+		v.Audience = "admin"
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), v) == ErrAudience)
+
+		v.Audience = ""
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), v) == ErrAudience)
+	})
+
+	t.Run("issuer", func(t *testing.T) {
+		v := validator(0)
+		v.Issuer = "other"
+		assert.True(t, coder.DecodeClaims(token, new(userClaims), v) == ErrIssuer)
+	})
+
+	t.Run("missing_expiry", func(t *testing.T) {
+		token, _ := coder.EncodeClaims(&Claims{Subject: "1"})
+		assert.True(t, coder.DecodeClaims(token, new(Claims), nil) == nil)
+		assert.True(t, coder.DecodeClaims(token, new(Claims), &Validator{RequireExpiry: true}) == ErrMissingExpiry)
+	})
+
+	t.Run("signature", func(t *testing.T) {
+		token[len(token)-1] ^= 1
+		err := coder.DecodeClaims(token, new(userClaims), validator(0))
+		token[len(token)-1] ^= 1
+		assert.True(t, err != nil)
+	})
+
+	t.Run("input", func(t *testing.T) {
+		assert.True(t, coder.DecodeClaims(coder.Encode([]byte("[]")), new(userClaims), nil) == ErrInput)
+	})
+}
+
+func Test_Audience(t *testing.T) {
+	var c Claims
+
+	assert.True(t, json.Unmarshal([]byte(`{"aud":"api"}`), &c) == nil)
+	assert.DeepEqual(t, c.Audience, Audience{"api"})
+
+	assert.True(t, json.Unmarshal([]byte(`{"aud":["api","forum"]}`), &c) == nil)
+	assert.DeepEqual(t, c.Audience, Audience{"api", "forum"})
+
+	assert.True(t, json.Unmarshal([]byte(`{"aud":1}`), &c) != nil)
+}
@@ -44,6 +44,40 @@ func (c *Coder) Encode(input []byte) []byte {
 }
 
 func (c *Coder) Decode(input []byte) ([]byte, error) {
+	return decode(c.publicKey, input)
+}
+
+func (c *Coder) Verifier() *Verifier {
+	return &Verifier{publicKey: c.publicKey}
+}
+
+type Verifier struct {
+	publicKey ed25519.PublicKey
+}
+
+func (v *Verifier) PublicKey() Key {
+	return Key(v.publicKey)
+}
+
+func (v *Verifier) Decode(input []byte) ([]byte, error) {
+	return decode(v.publicKey, input)
+}
+
+func (v *Verifier) DecodeClaims(input []byte, claims ClaimsHolder, validator *Validator) error {
+	payload, err := v.Decode(input)
+	if err != nil {
+		return err
+	}
+	return unmarshalClaims(payload, claims, validator)
+}
+
+func (v *Verifier) DecodeJWT(input []byte, claims ClaimsHolder, validator *Validator) error {
+	return decodeJWT(input, func(kid string) ([]ed25519.PublicKey, error) {
+		return []ed25519.PublicKey{v.publicKey}, nil
+	}, claims, validator)
+}
+
+func decode(publicKey ed25519.PublicKey, input []byte) ([]byte, error) {
 	dotIndex := bytes.IndexByte(input, '.')
 	if dotIndex < 2 {
 		return nil, ErrInput
@@ -53,7 +87,7 @@ func (c *Coder) Decode(input []byte) ([]byte, error) {
 		return nil, err
 	}
 	output64 := input[:dotIndex]
-	if !ed25519.Verify(c.publicKey, output64, sign) {
+	if !ed25519.Verify(publicKey, output64, sign) {
 		return nil, ErrSign
 	}
 	output, err := base64.RawURLEncoding.DecodeString(string(output64))
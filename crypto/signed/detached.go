@@ -0,0 +1,114 @@
+package signed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+const (
+	detachedPrefix = "sha512-ed25519 "
+	detachedDomain = "signed/detached/v1\x00"
+
+	SignatureFileExt = ".sig"
+)
+
+// streams data into the SHA-512 prehash that detached signatures cover
+type Digest struct {
+	hash hash.Hash
+}
+
+func NewDigest() *Digest {
+	return &Digest{hash: sha512.New()}
+}
+
+func (d *Digest) Write(p []byte) (int, error) {
+	return d.hash.Write(p)
+}
+
+func (d *Digest) message() []byte {
+	return d.hash.Sum([]byte(detachedDomain))
+}
+
+func (c *Coder) SignDigest(d *Digest) []byte {
+	sign := ed25519.Sign(c.privateKey, d.message())
+	output := make([]byte, len(detachedPrefix)+base64.StdEncoding.EncodedLen(len(sign))+1)
+	copy(output, detachedPrefix)
+	base64.StdEncoding.Encode(output[len(detachedPrefix):], sign)
+	output[len(output)-1] = '\n'
+	return output
+}
+
+func (v *Verifier) VerifyDigest(d *Digest, signature []byte) error {
+	signature = bytes.TrimSpace(signature)
+	if !bytes.HasPrefix(signature, []byte(detachedPrefix)) {
+		return ErrInput
+	}
+	sign, err := base64.StdEncoding.DecodeString(string(signature[len(detachedPrefix):]))
+	if err != nil || len(sign) != ed25519.SignatureSize {
+		return ErrInput
+	}
+	if !ed25519.Verify(v.publicKey, d.message(), sign) {
+		return ErrSign
+	}
+	return nil
+}
+
+func (c *Coder) SignDetached(r io.Reader) ([]byte, error) {
+	d := NewDigest()
+	if _, err := io.Copy(d, r); err != nil {
+		return nil, err
+	}
+	return c.SignDigest(d), nil
+}
+
+func (v *Verifier) VerifyDetached(r io.Reader, signature []byte) error {
+	d := NewDigest()
+	if _, err := io.Copy(d, r); err != nil {
+		return err
+	}
+	return v.VerifyDigest(d, signature)
+}
+
+func (c *Coder) VerifyDetached(r io.Reader, signature []byte) error {
+	return c.Verifier().VerifyDetached(r, signature)
+}
+
+// signatureFile defaults to file + ".sig"
+func (c *Coder) SignFile(file, signatureFile string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	signature, err := c.SignDetached(f)
+	if err != nil {
+		return err
+	}
+	if signatureFile == "" {
+		signatureFile = file + SignatureFileExt
+	}
+	return ioutil.WriteFile(signatureFile, signature, 0644)
+}
+
+// signatureFile defaults to file + ".sig"
+func (v *Verifier) VerifyFile(file, signatureFile string) error {
+	if signatureFile == "" {
+		signatureFile = file + SignatureFileExt
+	}
+	signature, err := ioutil.ReadFile(signatureFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return v.VerifyDetached(f, signature)
+}
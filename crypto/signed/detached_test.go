@@ -0,0 +1,81 @@
+package signed
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Detached(t *testing.T) {
+	coder, _ := Generate()
+	verifier := coder.Verifier()
+
+	data := bytes.Repeat([]byte("fantlab dump "), 100000)
+
+	signature, err := coder.SignDetached(bytes.NewReader(data))
+
+	assert.True(t, err == nil)
+	assert.True(t, strings.HasPrefix(string(signature), "sha512-ed25519 ") && len(signature) < 128)
+
+	t.Run("positive", func(t *testing.T) {
+		assert.True(t, verifier.VerifyDetached(bytes.NewReader(data), signature) == nil)
+		assert.True(t, coder.VerifyDetached(bytes.NewReader(data), bytes.TrimSpace(signature)) == nil)
+	})
+
+	t.Run("streaming", func(t *testing.T) {
+		d := NewDigest()
+		w := io.MultiWriter(ioutil.Discard, d)
+		for i := 0; i < len(data); i += 4096 {
+			end := i + 4096
+			if end > len(data) {
+				end = len(data)
+			}
+			_, _ = w.Write(data[i:end])
+		}
+		assert.True(t, verifier.VerifyDigest(d, signature) == nil)
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		modified := append([]byte{}, data...)
+		modified[len(modified)/2] ^= 1
+		assert.True(t, verifier.VerifyDetached(bytes.NewReader(modified), signature) == ErrSign)
+	})
+
+	t.Run("other_key", func(t *testing.T) {
+		other, _ := Generate()
+		assert.True(t, other.VerifyDetached(bytes.NewReader(data), signature) == ErrSign)
+	})
+
+	t.Run("not_a_token_signature", func(t *testing.T) {
+		token := coder.Encode(data[:32])
+		assert.True(t, verifier.VerifyDetached(bytes.NewReader(data[:32]), token) == ErrInput)
+		assert.True(t, verifier.VerifyDetached(bytes.NewReader(data), []byte("sha512-ed25519 ***")) == ErrInput)
+	})
+}
+
+func Test_DetachedFile(t *testing.T) {
+	coder, _ := Generate()
+
+	dir, _ := ioutil.TempDir("", "detached")
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "dump.sql")
+	_ = ioutil.WriteFile(file, []byte("INSERT INTO works VALUES (1);"), 0644)
+
+	assert.True(t, coder.SignFile(file, "") == nil)
+
+	_, err := os.Stat(file + SignatureFileExt)
+	assert.True(t, err == nil)
+
+	assert.True(t, coder.Verifier().VerifyFile(file, "") == nil)
+
+	_ = ioutil.WriteFile(file, []byte("DROP TABLE works;"), 0644)
+
+	assert.True(t, coder.Verifier().VerifyFile(file, "") == ErrSign)
+}
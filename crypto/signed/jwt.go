@@ -0,0 +1,192 @@
+package signed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+)
+
+var ErrAlgorithm = errors.New("signed: unsupported algorithm")
+
+const (
+	jwtAlgorithm = "EdDSA"
+	jwtType      = "JWT"
+)
+
+type jwtHeader struct {
+	Algorithm string   `json:"alg"`
+	Type      string   `json:"typ,omitempty"`
+	KeyID     string   `json:"kid,omitempty"`
+	Critical  []string `json:"crit,omitempty"`
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Curve     string `json:"crv"`
+	X         string `json:"x"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (s JWKS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	_ = json.NewEncoder(w).Encode(s)
+}
+
+func (key Key) JWK(kid string) JWK {
+	return JWK{
+		KeyType:   "OKP",
+		Curve:     "Ed25519",
+		X:         base64.RawURLEncoding.EncodeToString(key),
+		KeyID:     kid,
+		Use:       "sig",
+		Algorithm: jwtAlgorithm,
+	}
+}
+
+// RFC 7638 JWK thumbprint of a public key
+func (key Key) Thumbprint() string {
+	sum := sha256.Sum256([]byte(`{"crv":"Ed25519","kty":"OKP","x":"` + base64.RawURLEncoding.EncodeToString(key) + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// the key id is the public key thumbprint
+func (c *Coder) EncodeJWT(claims ClaimsHolder) ([]byte, error) {
+	return encodeJWT(c.privateKey, c.PublicKey().Thumbprint(), claims)
+}
+
+func (c *Coder) DecodeJWT(input []byte, claims ClaimsHolder, v *Validator) error {
+	return decodeJWT(input, func(kid string) ([]ed25519.PublicKey, error) {
+		return []ed25519.PublicKey{c.publicKey}, nil
+	}, claims, v)
+}
+
+func (c *Coder) JWKS() JWKS {
+	key := c.PublicKey()
+	return JWKS{Keys: []JWK{key.JWK(key.Thumbprint())}}
+}
+
+func (k *Keyring) EncodeJWT(claims ClaimsHolder) ([]byte, error) {
+	k.mu.RLock()
+	id := k.current
+	entry := k.keys[id]
+	k.mu.RUnlock()
+
+	if entry == nil {
+		return nil, ErrNoCurrentKey
+	}
+
+	return encodeJWT(entry.privateKey, id, claims)
+}
+
+func (k *Keyring) DecodeJWT(input []byte, claims ClaimsHolder, v *Validator) error {
+	return decodeJWT(input, func(kid string) ([]ed25519.PublicKey, error) {
+		k.mu.RLock()
+		defer k.mu.RUnlock()
+
+		if kid != "" {
+			entry := k.keys[kid]
+			if entry == nil {
+				return nil, ErrUnknownKey
+			}
+			return []ed25519.PublicKey{entry.publicKey}, nil
+		}
+
+		keys := make([]ed25519.PublicKey, 0, len(k.keys))
+		for _, entry := range k.keys {
+			keys = append(keys, entry.publicKey)
+		}
+		return keys, nil
+	}, claims, v)
+}
+
+func (k *Keyring) JWKS() JWKS {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	s := JWKS{Keys: make([]JWK, 0, len(k.keys))}
+
+	for id, entry := range k.keys {
+		s.Keys = append(s.Keys, Key(entry.publicKey).JWK(id))
+	}
+
+	sort.Slice(s.Keys, func(i, j int) bool {
+		return s.Keys[i].KeyID < s.Keys[j].KeyID
+	})
+
+	return s
+}
+
+func encodeJWT(privateKey ed25519.PrivateKey, kid string, claims ClaimsHolder) ([]byte, error) {
+	header, err := json.Marshal(jwtHeader{Algorithm: jwtAlgorithm, Type: jwtType, KeyID: kid})
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := base64.RawURLEncoding.EncodedLen(len(header))
+	payloadSize := base64.RawURLEncoding.EncodedLen(len(payload))
+	signed := headerSize + 1 + payloadSize
+	output := make([]byte, signed+1+signatureSize)
+	base64.RawURLEncoding.Encode(output, header)
+	output[headerSize] = '.'
+	base64.RawURLEncoding.Encode(output[headerSize+1:], payload)
+	output[signed] = '.'
+	sign := ed25519.Sign(privateKey, output[:signed])
+	base64.RawURLEncoding.Encode(output[signed+1:], sign)
+	return output, nil
+}
+
+func decodeJWT(input []byte, lookup func(kid string) ([]ed25519.PublicKey, error), claims ClaimsHolder, v *Validator) error {
+	parts := bytes.Split(input, []byte{'.'})
+	if len(parts) != 3 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return ErrInput
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(string(parts[0]))
+	if err != nil {
+		return ErrInput
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return ErrInput
+	}
+	// rejects "none", HMAC algorithms keyed with the public key and unknown extensions
+	if header.Algorithm != jwtAlgorithm || len(header.Critical) > 0 {
+		return ErrAlgorithm
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(string(parts[2]))
+	if err != nil || len(sign) != ed25519.SignatureSize {
+		return ErrSign
+	}
+	keys, err := lookup(header.KeyID)
+	if err != nil {
+		return err
+	}
+	signed := input[:len(parts[0])+1+len(parts[1])]
+	verified := false
+	for _, key := range keys {
+		if verified = ed25519.Verify(key, signed, sign); verified {
+			break
+		}
+	}
+	if !verified {
+		return ErrSign
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(string(parts[1]))
+	if err != nil {
+		return ErrInput
+	}
+	return unmarshalClaims(payload, claims, v)
+}
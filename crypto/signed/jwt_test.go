@@ -0,0 +1,129 @@
+package signed
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_JWT(t *testing.T) {
+	coder, _ := Generate()
+
+	token, err := coder.EncodeJWT(&Claims{Subject: "42"})
+
+	assert.True(t, err == nil)
+
+	t.Run("format", func(t *testing.T) {
+		parts := strings.Split(string(token), ".")
+		assert.True(t, len(parts) == 3)
+
+		header, _ := base64.RawURLEncoding.DecodeString(parts[0])
+		assert.True(t, string(header) == `{"alg":"EdDSA","typ":"JWT","kid":"`+coder.PublicKey().Thumbprint()+`"}`)
+
+		payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		assert.True(t, string(payload) == `{"sub":"42"}`)
+
+		sign, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		assert.True(t, ed25519.Verify(coder.publicKey, token[:len(parts[0])+1+len(parts[1])], sign))
+	})
+
+	t.Run("positive", func(t *testing.T) {
+		var c Claims
+		assert.True(t, coder.DecodeJWT(token, &c, nil) == nil && c.Subject == "42")
+	})
+
+	forge := func(header string, sign func(signed string) []byte) []byte {
+		signed := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1"}`))
+		return []byte(signed + "." + base64.RawURLEncoding.EncodeToString(sign(signed)))
+	}
+
+	t.Run("alg_none", func(t *testing.T) {
+		x := forge(`{"alg":"none"}`, func(string) []byte { return nil })
+		assert.True(t, coder.DecodeJWT(x, new(Claims), nil) == ErrAlgorithm)
+	})
+
+	t.Run("algorithm_confusion", func(t *testing.T) {
+		x := forge(`{"alg":"HS256"}`, func(signed string) []byte {
+			mac := hmac.New(sha256.New, coder.publicKey)
+			mac.Write([]byte(signed))
+			return mac.Sum(nil)
+		})
+		assert.True(t, coder.DecodeJWT(x, new(Claims), nil) == ErrAlgorithm)
+	})
+
+	t.Run("crit", func(t *testing.T) {
+		x := forge(`{"alg":"EdDSA","crit":["exp"]}`, func(signed string) []byte {
+			return ed25519.Sign(coder.privateKey, []byte(signed))
+		})
+		assert.True(t, coder.DecodeJWT(x, new(Claims), nil) == ErrAlgorithm)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		other, _ := Generate()
+		x, _ := other.EncodeJWT(&Claims{Subject: "42"})
+		assert.True(t, coder.DecodeJWT(x, new(Claims), nil) == ErrSign)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		assert.True(t, coder.DecodeJWT([]byte("a.b"), new(Claims), nil) == ErrInput)
+		assert.True(t, coder.DecodeJWT(coder.Encode([]byte("{}")), new(Claims), nil) == ErrInput)
+	})
+}
+
+func Test_KeyringJWT(t *testing.T) {
+	a, _ := Generate()
+	b, _ := Generate()
+
+	k := NewKeyring()
+	_ = k.Add("b", b)
+	_ = k.Add("a", a)
+
+	token, err := k.EncodeJWT(&Claims{Subject: "42"})
+	assert.True(t, err == nil)
+
+	var c Claims
+	assert.True(t, k.DecodeJWT(token, &c, nil) == nil && c.Subject == "42")
+
+	k.Remove("b")
+	assert.True(t, k.DecodeJWT(token, &c, nil) == ErrUnknownKey)
+
+	anonymous, _ := encodeJWT(a.privateKey, "", &Claims{})
+	assert.True(t, k.DecodeJWT(anonymous, &c, nil) == nil)
+}
+
+func Test_JWKS(t *testing.T) {
+	a, _ := Generate()
+	b, _ := Generate()
+
+	k := NewKeyring()
+	_ = k.Add("b", b)
+	_ = k.Add("a", a)
+
+	w := httptest.NewRecorder()
+	k.JWKS().ServeHTTP(w, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+
+	assert.True(t, w.Header().Get("Content-Type") == "application/jwk-set+json")
+
+	var s JWKS
+	assert.True(t, json.Unmarshal(w.Body.Bytes(), &s) == nil)
+	assert.True(t, len(s.Keys) == 2 && s.Keys[0].KeyID == "a" && s.Keys[1].KeyID == "b")
+
+	x, _ := base64.RawURLEncoding.DecodeString(s.Keys[0].X)
+	assert.True(t, s.Keys[0].KeyType == "OKP" && s.Keys[0].Curve == "Ed25519" && string(x) == string(a.publicKey))
+
+	assert.True(t, a.JWKS().Keys[0].KeyID == a.PublicKey().Thumbprint())
+}
+
+func Test_Thumbprint(t *testing.T) {
+	// RFC 8037 appendix A.3
+	x, _ := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+
+	assert.True(t, Key(x).Thumbprint() == "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k")
+}
@@ -0,0 +1,296 @@
+package signed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrKeyID        = errors.New("signed: invalid key id")
+	ErrUnknownKey   = errors.New("signed: unknown key id")
+	ErrRetiringKey  = errors.New("signed: key is retiring")
+	ErrNoCurrentKey = errors.New("signed: no current key")
+)
+
+const (
+	publicKeyExt   = ".pub"
+	privateKeyExt  = ".key"
+	currentKeyFile = "current"
+)
+
+type keyringEntry struct {
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+	retiring   bool
+}
+
+// tokens are "kid.payload.signature", the signature covers the key id
+type Keyring struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]*keyringEntry
+}
+
+func NewKeyring() *Keyring {
+	return &Keyring{keys: make(map[string]*keyringEntry)}
+}
+
+// the first added key becomes current
+func (k *Keyring) Add(id string, c *Coder) error {
+	return k.add(id, &keyringEntry{publicKey: c.publicKey, privateKey: c.privateKey})
+}
+
+// verify-only keys are always retiring
+func (k *Keyring) AddPublicKey(id string, publicKey []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return ErrPublicKeySize
+	}
+	return k.add(id, &keyringEntry{publicKey: publicKey, retiring: true})
+}
+
+func (k *Keyring) add(id string, entry *keyringEntry) error {
+	if !validKeyID(id) {
+		return ErrKeyID
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys[id] = entry
+
+	if k.current == "" && !entry.retiring {
+		k.current = id
+	}
+
+	return nil
+}
+
+func (k *Keyring) SetCurrent(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	entry := k.keys[id]
+
+	if entry == nil {
+		return ErrUnknownKey
+	}
+	if entry.retiring {
+		return ErrRetiringKey
+	}
+
+	k.current = id
+
+	return nil
+}
+
+// retiring keys still verify tokens but never sign new ones
+func (k *Keyring) Retire(id string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	entry := k.keys[id]
+
+	if entry == nil {
+		return ErrUnknownKey
+	}
+
+	entry.retiring = true
+
+	if k.current == id {
+		k.current = ""
+	}
+
+	return nil
+}
+
+func (k *Keyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	delete(k.keys, id)
+
+	if k.current == id {
+		k.current = ""
+	}
+}
+
+func (k *Keyring) Current() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.current
+}
+
+func (k *Keyring) PublicKeys() map[string]Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	keys := make(map[string]Key, len(k.keys))
+
+	for id, entry := range k.keys {
+		keys[id] = Key(entry.publicKey)
+	}
+
+	return keys
+}
+
+func (k *Keyring) Encode(input []byte) ([]byte, error) {
+	k.mu.RLock()
+	id := k.current
+	entry := k.keys[id]
+	k.mu.RUnlock()
+
+	if entry == nil {
+		return nil, ErrNoCurrentKey
+	}
+
+	size := base64.RawURLEncoding.EncodedLen(len(input))
+	output := make([]byte, len(id)+1+size+1+signatureSize)
+	copy(output, id)
+	output[len(id)] = '.'
+	base64.RawURLEncoding.Encode(output[len(id)+1:], input)
+	signed := len(id) + 1 + size
+	output[signed] = '.'
+	sign := ed25519.Sign(entry.privateKey, output[:signed])
+	base64.RawURLEncoding.Encode(output[signed+1:], sign)
+	return output, nil
+}
+
+// two-part tokens of a plain Coder are checked against every key
+func (k *Keyring) Decode(input []byte) ([]byte, error) {
+	lastDotIndex := bytes.LastIndexByte(input, '.')
+	if lastDotIndex < 2 {
+		return nil, ErrInput
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(string(input[lastDotIndex+1:]))
+	if err != nil {
+		return nil, err
+	}
+	signed := input[:lastDotIndex]
+	output64 := signed
+
+	k.mu.RLock()
+
+	var verified bool
+
+	if dotIndex := bytes.IndexByte(signed, '.'); dotIndex >= 0 {
+		entry := k.keys[string(signed[:dotIndex])]
+		if entry == nil {
+			k.mu.RUnlock()
+			return nil, ErrUnknownKey
+		}
+		verified = ed25519.Verify(entry.publicKey, signed, sign)
+		output64 = signed[dotIndex+1:]
+	} else {
+		for _, entry := range k.keys {
+			if verified = ed25519.Verify(entry.publicKey, signed, sign); verified {
+				break
+			}
+		}
+	}
+
+	k.mu.RUnlock()
+
+	if !verified {
+		return nil, ErrSign
+	}
+	output, err := base64.RawURLEncoding.DecodeString(string(output64))
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+func (k *Keyring) EncodeClaims(claims ClaimsHolder) ([]byte, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	return k.Encode(payload)
+}
+
+func (k *Keyring) DecodeClaims(input []byte, claims ClaimsHolder, v *Validator) error {
+	payload, err := k.Decode(input)
+	if err != nil {
+		return err
+	}
+	return unmarshalClaims(payload, claims, v)
+}
+
+// loads "<id>.pub" and "<id>.key" base64 pairs as written for NewFileCoder64,
+// a lone "<id>.pub" adds a verify-only key, an optional "current" file names
+// the signing key, otherwise the greatest id is used
+func NewFileKeyring64(dir string) (*Keyring, error) {
+	publicKeyFiles, err := filepath.Glob(filepath.Join(dir, "*"+publicKeyExt))
+	if err != nil {
+		return nil, err
+	}
+
+	k := NewKeyring()
+
+	var ids []string
+
+	for _, publicKeyFile := range publicKeyFiles {
+		id := strings.TrimSuffix(filepath.Base(publicKeyFile), publicKeyExt)
+		privateKeyFile := filepath.Join(dir, id+privateKeyExt)
+
+		if _, err := os.Stat(privateKeyFile); os.IsNotExist(err) {
+			publicKey64, err := ioutil.ReadFile(publicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, err := base64.StdEncoding.DecodeString(string(publicKey64))
+			if err != nil {
+				return nil, err
+			}
+			if err := k.AddPublicKey(id, publicKey); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		coder, err := NewFileCoder64(publicKeyFile, privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := k.Add(id, coder); err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	if current, err := ioutil.ReadFile(filepath.Join(dir, currentKeyFile)); err == nil {
+		if err := k.SetCurrent(strings.TrimSpace(string(current))); err != nil {
+			return nil, err
+		}
+	} else if len(ids) > 0 {
+		sort.Strings(ids)
+
+		k.current = ids[len(ids)-1]
+	}
+
+	return k, nil
+}
+
+func validKeyID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package signed
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Keyring(t *testing.T) {
+	k := NewKeyring()
+
+	_, err := k.Encode([]byte("x"))
+	assert.True(t, err == ErrNoCurrentKey)
+
+	old, _ := Generate()
+	next, _ := Generate()
+
+	assert.True(t, k.Add("2020", old) == nil)
+	assert.True(t, k.Add("2021", next) == nil)
+	assert.True(t, k.Add("bad.id", next) == ErrKeyID)
+	assert.True(t, k.Current() == "2020")
+
+	oldToken, _ := k.Encode([]byte("old"))
+
+	t.Run("rotation", func(t *testing.T) {
+		assert.True(t, k.SetCurrent("2021") == nil)
+		assert.True(t, k.Retire("2020") == nil)
+		assert.True(t, k.SetCurrent("2020") == ErrRetiringKey)
+
+		token, err := k.Encode([]byte("next"))
+		assert.True(t, err == nil)
+		assert.True(t, string(token[:5]) == "2021.")
+
+		y, err := k.Decode(token)
+		assert.True(t, err == nil && string(y) == "next")
+
+		y, err = k.Decode(oldToken)
+		assert.True(t, err == nil && string(y) == "old")
+	})
+
+	t.Run("plain_coder_tokens", func(t *testing.T) {
+		y, err := k.Decode(old.Encode([]byte("legacy")))
+		assert.True(t, err == nil && string(y) == "legacy")
+
+		other, _ := Generate()
+		_, err = k.Decode(other.Encode([]byte("legacy")))
+		assert.True(t, err == ErrSign)
+	})
+
+	t.Run("key_id_is_signed", func(t *testing.T) {
+		token, _ := k.Encode([]byte("next"))
+		token[3] = '0'
+		_, err := k.Decode(token)
+		assert.True(t, err == ErrSign)
+	})
+
+	t.Run("removed", func(t *testing.T) {
+		k.Remove("2020")
+		_, err := k.Decode(oldToken)
+		assert.True(t, err == ErrUnknownKey)
+	})
+
+	t.Run("claims", func(t *testing.T) {
+		token, err := k.EncodeClaims(&Claims{Subject: "42"})
+		assert.True(t, err == nil)
+
+		var c Claims
+		assert.True(t, k.DecodeClaims(token, &c, nil) == nil && c.Subject == "42")
+	})
+}
+
+func Test_FileKeyring64(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "keyring")
+	defer os.RemoveAll(dir)
+
+	write := func(name string, key Key) {
+		_ = ioutil.WriteFile(filepath.Join(dir, name), []byte(key.String()), 0600)
+	}
+
+	a, _ := Generate()
+	b, _ := Generate()
+	c, _ := Generate()
+
+	write("a.pub", a.PublicKey())
+	write("a.key", a.PrivateKey())
+	write("b.pub", b.PublicKey())
+	write("b.key", b.PrivateKey())
+	write("c.pub", c.PublicKey())
+
+	k, err := NewFileKeyring64(dir)
+
+	assert.True(t, err == nil)
+	assert.True(t, k.Current() == "b")
+	assert.True(t, len(k.PublicKeys()) == 3)
+
+	_, err = k.Decode(c.Encode([]byte("verify only")))
+	assert.True(t, err == nil)
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "current"), []byte("a\n"), 0600)
+
+	k, err = NewFileKeyring64(dir)
+	assert.True(t, err == nil && k.Current() == "a")
+
+	_ = ioutil.WriteFile(filepath.Join(dir, "current"), []byte("c"), 0600)
+
+	_, err = NewFileKeyring64(dir)
+	assert.True(t, err == ErrRetiringKey)
+}
@@ -0,0 +1,45 @@
+package signed
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+)
+
+const messagePrefix = "signed/message/v1\x00"
+
+// signs prefix, domain and message joined by NUL bytes, so the signature is never valid
+// for a token, a detached signature or a message of another domain
+func (c *Coder) SignMessage(domain string, message []byte) []byte {
+	sign := ed25519.Sign(c.privateKey, messageDomain(domain, message))
+	output := make([]byte, signatureSize)
+	base64.RawURLEncoding.Encode(output, sign)
+	return output
+}
+
+func (v *Verifier) VerifyMessage(domain string, message, signature []byte) error {
+	sign, err := base64.RawURLEncoding.DecodeString(string(signature))
+	if err != nil || len(sign) != ed25519.SignatureSize {
+		return ErrInput
+	}
+	if !ed25519.Verify(v.publicKey, messageDomain(domain, message), sign) {
+		return ErrSign
+	}
+	return nil
+}
+
+func (c *Coder) VerifyMessage(domain string, message, signature []byte) error {
+	return c.Verifier().VerifyMessage(domain, message, signature)
+}
+
+// domain must not contain NUL, it would make the boundary with the message ambiguous
+func messageDomain(domain string, message []byte) []byte {
+	if strings.IndexByte(domain, 0) >= 0 {
+		panic("signed: NUL in message domain")
+	}
+	output := make([]byte, 0, len(messagePrefix)+len(domain)+1+len(message))
+	output = append(output, messagePrefix...)
+	output = append(output, domain...)
+	output = append(output, 0)
+	return append(output, message...)
+}
@@ -0,0 +1,40 @@
+package signed
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Message(t *testing.T) {
+	coder, _ := Generate()
+	verifier := coder.Verifier()
+
+	message := []byte("GET\n/exports/42")
+	signature := coder.SignMessage("presign", message)
+
+	t.Run("positive", func(t *testing.T) {
+		assert.True(t, verifier.VerifyMessage("presign", message, signature) == nil)
+		assert.True(t, coder.VerifyMessage("presign", message, signature) == nil)
+	})
+
+	t.Run("domain", func(t *testing.T) {
+		assert.True(t, verifier.VerifyMessage("export", message, signature) == ErrSign)
+	})
+
+	t.Run("message", func(t *testing.T) {
+		assert.True(t, verifier.VerifyMessage("presign", []byte("GET\n/exports/43"), signature) == ErrSign)
+	})
+
+	t.Run("not_a_token", func(t *testing.T) {
+		token := base64.RawURLEncoding.EncodeToString(message) + "." + string(signature)
+		_, err := coder.Decode([]byte(token))
+		assert.True(t, err == ErrSign)
+	})
+
+	t.Run("input", func(t *testing.T) {
+		assert.True(t, verifier.VerifyMessage("presign", message, signature[1:]) == ErrInput)
+		assert.True(t, verifier.VerifyMessage("presign", message, []byte("!")) == ErrInput)
+	})
+}
@@ -54,3 +54,26 @@ func Generate() (*Coder, error) {
 	}
 	return &Coder{publicKey: publicKey, privateKey: privateKey}, nil
 }
+
+func NewFileVerifier64(publicKeyFile string) (*Verifier, error) {
+	publicKey64, err := ioutil.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier64(string(publicKey64))
+}
+
+func NewVerifier64(publicKey64 string) (*Verifier, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(publicKey64)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(publicKey)
+}
+
+func NewVerifier(publicKey []byte) (*Verifier, error) {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrPublicKeySize
+	}
+	return &Verifier{publicKey: publicKey}, nil
+}
@@ -0,0 +1,199 @@
+package signed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+)
+
+var (
+	ErrKeyFormat    = errors.New("signed: unsupported key format")
+	ErrEncryptedKey = errors.New("signed: encrypted keys are not supported")
+)
+
+const (
+	publicKeyBlock  = "PUBLIC KEY"
+	privateKeyBlock = "PRIVATE KEY"
+	openSSHBlock    = "OPENSSH PRIVATE KEY"
+	openSSHMagic    = "openssh-key-v1\x00"
+	openSSHKeyType  = "ssh-ed25519"
+)
+
+func NewFileCoderPEM(privateKeyFile string) (*Coder, error) {
+	data, err := ioutil.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewCoderPEM(data)
+}
+
+// accepts PKCS8 "PRIVATE KEY" and unencrypted "OPENSSH PRIVATE KEY" blocks
+func NewCoderPEM(privateKeyPEM []byte) (*Coder, error) {
+	privateKey, err := ParsePrivateKeyPEM(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return NewCoder(ed25519.PrivateKey(privateKey).Public().(ed25519.PublicKey), privateKey)
+}
+
+func NewFileVerifierPEM(publicKeyFile string) (*Verifier, error) {
+	data, err := ioutil.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifierPEM(data)
+}
+
+// accepts PKIX "PUBLIC KEY" blocks and "ssh-ed25519" authorized_keys lines
+func NewVerifierPEM(publicKeyPEM []byte) (*Verifier, error) {
+	publicKey, err := ParsePublicKeyPEM(publicKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifier(publicKey)
+}
+
+func ParsePublicKeyPEM(data []byte) (Key, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(openSSHKeyType+" ")) {
+		return parseOpenSSHPublicKey(data)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != publicKeyBlock {
+		return nil, ErrKeyFormat
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, ErrKeyFormat
+	}
+	return Key(publicKey), nil
+}
+
+func ParsePrivateKeyPEM(data []byte) (Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrKeyFormat
+	}
+	switch block.Type {
+	case privateKeyBlock:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, ErrKeyFormat
+		}
+		return Key(privateKey), nil
+	case openSSHBlock:
+		return parseOpenSSHPrivateKey(block.Bytes)
+	}
+	return nil, ErrKeyFormat
+}
+
+func MarshalPublicKeyPEM(publicKey Key) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(ed25519.PublicKey(publicKey))
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyBlock, Bytes: der}), nil
+}
+
+func MarshalPrivateKeyPEM(privateKey Key) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(ed25519.PrivateKey(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyBlock, Bytes: der}), nil
+}
+
+func parseOpenSSHPublicKey(data []byte) (Key, error) {
+	fields := bytes.Fields(data)
+	if len(fields) < 2 {
+		return nil, ErrKeyFormat
+	}
+	blob, err := base64.StdEncoding.DecodeString(string(fields[1]))
+	if err != nil {
+		return nil, ErrKeyFormat
+	}
+	r := sshReader{data: blob}
+	if string(r.next()) != openSSHKeyType {
+		return nil, ErrKeyFormat
+	}
+	publicKey := r.next()
+	if r.err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil, ErrKeyFormat
+	}
+	return Key(publicKey), nil
+}
+
+// see PROTOCOL.key in the OpenSSH sources
+func parseOpenSSHPrivateKey(data []byte) (Key, error) {
+	if !bytes.HasPrefix(data, []byte(openSSHMagic)) {
+		return nil, ErrKeyFormat
+	}
+	r := sshReader{data: data[len(openSSHMagic):]}
+	cipherName, kdfName := r.next(), r.next()
+	r.next() // kdf options
+	keyCount := r.uint32()
+	r.next() // public key blob
+	private := sshReader{data: r.next()}
+	if r.err != nil {
+		return nil, ErrKeyFormat
+	}
+	if string(cipherName) != "none" || string(kdfName) != "none" {
+		return nil, ErrEncryptedKey
+	}
+	if keyCount != 1 {
+		return nil, ErrKeyFormat
+	}
+	check1, check2 := private.uint32(), private.uint32()
+	if check1 != check2 {
+		return nil, ErrKeyFormat
+	}
+	if string(private.next()) != openSSHKeyType {
+		return nil, ErrKeyFormat
+	}
+	publicKey, privateKey := private.next(), private.next()
+	if private.err != nil || len(publicKey) != ed25519.PublicKeySize || len(privateKey) != ed25519.PrivateKeySize {
+		return nil, ErrKeyFormat
+	}
+	if !bytes.Equal(privateKey[ed25519.SeedSize:], publicKey) {
+		return nil, ErrKeyFormat
+	}
+	return Key(privateKey), nil
+}
+
+type sshReader struct {
+	data []byte
+	err  error
+}
+
+func (r *sshReader) uint32() uint32 {
+	if r.err != nil || len(r.data) < 4 {
+		r.err = ErrKeyFormat
+		return 0
+	}
+	n := binary.BigEndian.Uint32(r.data)
+	r.data = r.data[4:]
+	return n
+}
+
+func (r *sshReader) next() []byte {
+	n := r.uint32()
+	if r.err != nil || uint64(len(r.data)) < uint64(n) {
+		r.err = ErrKeyFormat
+		return nil
+	}
+	s := r.data[:n]
+	r.data = r.data[n:]
+	return s
+}
@@ -0,0 +1,123 @@
+package signed
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+var (
+	ErrSecretSize = errors.New("signed: invalid secret size")
+	ErrVersion    = errors.New("signed: unsupported token version")
+	ErrDecrypt    = errors.New("signed: decryption failed")
+)
+
+const (
+	SecretSize = 32
+
+	sealedVersion = "e1"
+)
+
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// encrypts payloads with AES-256-GCM and signs the ciphertext,
+// tokens are "e1.nonce+ciphertext.signature"
+type SealedCoder struct {
+	coder *Coder
+	aead  cipher.AEAD
+}
+
+func NewSealedCoder(coder *Coder, secret []byte) (*SealedCoder, error) {
+	if len(secret) != SecretSize {
+		return nil, ErrSecretSize
+	}
+	block, err := aes.NewCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SealedCoder{coder: coder, aead: aead}, nil
+}
+
+func (s *SealedCoder) Encode(input []byte) ([]byte, error) {
+	nonceSize := s.aead.NonceSize()
+	sealed := make([]byte, nonceSize, nonceSize+len(input)+s.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, sealed); err != nil {
+		return nil, err
+	}
+	sealed = s.aead.Seal(sealed, sealed, input, []byte(sealedVersion))
+
+	prefix := len(sealedVersion) + 1
+	size := base64.RawURLEncoding.EncodedLen(len(sealed))
+	output := make([]byte, prefix+size+1+signatureSize)
+	copy(output, sealedVersion)
+	output[prefix-1] = '.'
+	base64.RawURLEncoding.Encode(output[prefix:], sealed)
+	output[prefix+size] = '.'
+	sign := ed25519.Sign(s.coder.privateKey, output[:prefix+size])
+	base64.RawURLEncoding.Encode(output[prefix+size+1:], sign)
+	return output, nil
+}
+
+// signed-only tokens of the underlying Coder are decoded as is
+func (s *SealedCoder) Decode(input []byte) ([]byte, error) {
+	dotIndex := bytes.IndexByte(input, '.')
+	lastDotIndex := bytes.LastIndexByte(input, '.')
+	if dotIndex == lastDotIndex {
+		return s.coder.Decode(input)
+	}
+	if string(input[:dotIndex]) != sealedVersion {
+		return nil, ErrVersion
+	}
+	sign, err := base64.RawURLEncoding.DecodeString(string(input[lastDotIndex+1:]))
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(s.coder.publicKey, input[:lastDotIndex], sign) {
+		return nil, ErrSign
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(string(input[dotIndex+1 : lastDotIndex]))
+	if err != nil {
+		return nil, err
+	}
+	nonceSize := s.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, ErrInput
+	}
+	output, err := s.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(sealedVersion))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return output, nil
+}
+
+func (s *SealedCoder) EncodeClaims(claims ClaimsHolder) ([]byte, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.Encode(payload)
+}
+
+func (s *SealedCoder) DecodeClaims(input []byte, claims ClaimsHolder, v *Validator) error {
+	payload, err := s.Decode(input)
+	if err != nil {
+		return err
+	}
+	return unmarshalClaims(payload, claims, v)
+}
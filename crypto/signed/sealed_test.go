@@ -0,0 +1,68 @@
+package signed
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_SealedCoder(t *testing.T) {
+	coder, _ := Generate()
+	secret, _ := GenerateSecret()
+
+	sealer, err := NewSealedCoder(coder, secret)
+
+	assert.True(t, err == nil)
+
+	t.Run("positive", func(t *testing.T) {
+		x, err := sealer.Encode([]byte("user@example.com"))
+		assert.True(t, err == nil)
+		assert.True(t, bytes.HasPrefix(x, []byte("e1.")))
+		assert.True(t, !bytes.Contains(x, []byte(base64.RawURLEncoding.EncodeToString([]byte("user@example.com"))[:8])))
+
+		y, err := sealer.Decode(x)
+		assert.True(t, err == nil && string(y) == "user@example.com")
+	})
+
+	t.Run("signed_only", func(t *testing.T) {
+		y, err := sealer.Decode(coder.Encode([]byte("legacy")))
+		assert.True(t, err == nil && string(y) == "legacy")
+	})
+
+	t.Run("signature", func(t *testing.T) {
+		other, _ := Generate()
+		otherSealer, _ := NewSealedCoder(other, secret)
+		x, _ := otherSealer.Encode([]byte("x"))
+		_, err := sealer.Decode(x)
+		assert.True(t, err == ErrSign)
+	})
+
+	t.Run("secret", func(t *testing.T) {
+		otherSecret, _ := GenerateSecret()
+		otherSealer, _ := NewSealedCoder(coder, otherSecret)
+		x, _ := otherSealer.Encode([]byte("x"))
+		_, err := sealer.Decode(x)
+		assert.True(t, err == ErrDecrypt)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		signed := []byte("e9.AAAA")
+		sign := ed25519.Sign(coder.privateKey, signed)
+		_, err := sealer.Decode([]byte(string(signed) + "." + base64.RawURLEncoding.EncodeToString(sign)))
+		assert.True(t, err == ErrVersion)
+	})
+
+	t.Run("claims", func(t *testing.T) {
+		x, _ := sealer.EncodeClaims(&Claims{Subject: "42"})
+		var c Claims
+		assert.True(t, sealer.DecodeClaims(x, &c, nil) == nil && c.Subject == "42")
+	})
+
+	t.Run("secret_size", func(t *testing.T) {
+		_, err := NewSealedCoder(coder, []byte("short"))
+		assert.True(t, err == ErrSecretSize)
+	})
+}
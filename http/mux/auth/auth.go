@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+var (
+	ErrNoToken       = errors.New("auth: token not found")
+	ErrInvalidClaims = errors.New("auth: invalid claims")
+)
+
+// implemented by *signed.Coder
+type Decoder interface {
+	Decode(input []byte) ([]byte, error)
+}
+
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+
+type Config struct {
+	Decoder Decoder
+	// tried in order, defaults to the bearer token
+	Sources []Source
+	// returns a pointer the token payload is unmarshaled into as JSON, raw payload is kept when nil
+	NewClaims func() interface{}
+	// checks claims implementing signed.ClaimsHolder, a zero Validator is used when nil
+	Validator *signed.Validator
+	Validate  func(r *http.Request, claims interface{}) error
+	// lets requests without a token through unauthenticated
+	Optional            bool
+	UnauthorizedHandler ErrorHandler
+}
+
+type identity struct {
+	token   string
+	payload []byte
+	claims  interface{}
+}
+
+type identityKey struct{}
+
+func Middleware(cfg *Config) mux.Middleware {
+	sources := cfg.Sources
+	if len(sources) == 0 {
+		sources = []Source{FromBearer()}
+	}
+
+	unauthorized := cfg.UnauthorizedHandler
+	if unauthorized == nil {
+		unauthorized = Unauthorized
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := ""
+
+			for _, source := range sources {
+				if token = source(r); token != "" {
+					break
+				}
+			}
+
+			if token == "" {
+				if cfg.Optional {
+					next.ServeHTTP(w, r)
+				} else {
+					unauthorized(w, r, ErrNoToken)
+				}
+				return
+			}
+
+			id, err := authenticate(cfg, r, token)
+
+			if err != nil {
+				unauthorized(w, r, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey{}, id)))
+		})
+	}
+}
+
+func authenticate(cfg *Config, r *http.Request, token string) (*identity, error) {
+	payload, err := cfg.Decoder.Decode([]byte(token))
+
+	if err != nil {
+		return nil, err
+	}
+
+	id := &identity{token: token, payload: payload}
+
+	if cfg.NewClaims != nil {
+		id.claims = cfg.NewClaims()
+
+		if err := json.Unmarshal(payload, id.claims); err != nil {
+			return nil, ErrInvalidClaims
+		}
+
+		if holder, ok := id.claims.(signed.ClaimsHolder); ok {
+			v := cfg.Validator
+			if v == nil {
+				v = new(signed.Validator)
+			}
+			if err := v.Validate(holder.StandardClaims()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if cfg.Validate != nil {
+		if err := cfg.Validate(r, id.claims); err != nil {
+			return nil, err
+		}
+	}
+
+	return id, nil
+}
+
+// for groups nested under an optional Middleware
+func Required(unauthorized ErrorHandler) mux.Middleware {
+	if unauthorized == nil {
+		unauthorized = Unauthorized
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !Authenticated(r.Context()) {
+				unauthorized(w, r, ErrNoToken)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func Unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+}
+
+func Authenticated(ctx context.Context) bool {
+	_, ok := ctx.Value(identityKey{}).(*identity)
+	return ok
+}
+
+func GetToken(ctx context.Context) string {
+	if id, ok := ctx.Value(identityKey{}).(*identity); ok {
+		return id.token
+	}
+	return ""
+}
+
+func GetPayload(ctx context.Context) []byte {
+	if id, ok := ctx.Value(identityKey{}).(*identity); ok {
+		return id.payload
+	}
+	return nil
+}
+
+// claims, ok := auth.GetClaims(ctx).(*UserClaims)
+func GetClaims(ctx context.Context) interface{} {
+	if id, ok := ctx.Value(identityKey{}).(*identity); ok {
+		return id.claims
+	}
+	return nil
+}
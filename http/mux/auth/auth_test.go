@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+type userClaims struct {
+	UserID int64  `json:"uid"`
+	Login  string `json:"login"`
+}
+
+var errBanned = errors.New("banned")
+
+func Test_Middleware(t *testing.T) {
+	coder, _ := signed.Generate()
+
+	var lastErr error
+
+	rootGroup := new(mux.Group)
+
+	rootGroup.Middleware(Middleware(&Config{
+		Decoder:   coder,
+		Sources:   []Source{FromBearer(), FromCookie("token"), FromQuery("token")},
+		NewClaims: func() interface{} { return new(userClaims) },
+		Validate: func(r *http.Request, claims interface{}) error {
+			if claims.(*userClaims).UserID == 13 {
+				return errBanned
+			}
+			return nil
+		},
+		Optional: true,
+		UnauthorizedHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			lastErr = err
+			Unauthorized(w, r, err)
+		},
+	}))
+
+	rootGroup.Endpoint(http.MethodGet, "/forums", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("forums"))
+	}))
+
+	rootGroup.Subgroup(func(g *mux.Group) {
+		g.Middleware(Required(nil))
+
+		g.Endpoint(http.MethodPost, "/topics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims := GetClaims(r.Context()).(*userClaims)
+			_, _ = w.Write([]byte(claims.Login))
+		}))
+	})
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       rootGroup,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	token := string(coder.Encode([]byte(`{"uid":1,"login":"alice"}`)))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		lastErr = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("optional", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodGet, "/forums", nil))
+		assert.True(t, w.Code == http.StatusOK && w.Body.String() == "forums")
+	})
+
+	t.Run("required", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodPost, "/topics", nil))
+		assert.True(t, w.Code == http.StatusUnauthorized)
+		assert.True(t, w.Header().Get("WWW-Authenticate") == "Bearer")
+	})
+
+	t.Run("bearer", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/topics", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := serve(r)
+		assert.True(t, w.Code == http.StatusOK && w.Body.String() == "alice")
+	})
+
+	t.Run("cookie", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/topics", nil)
+		r.AddCookie(&http.Cookie{Name: "token", Value: token})
+		w := serve(r)
+		assert.True(t, w.Code == http.StatusOK && w.Body.String() == "alice")
+	})
+
+	t.Run("query", func(t *testing.T) {
+		w := serve(httptest.NewRequest(http.MethodPost, "/topics?token="+token, nil))
+		assert.True(t, w.Code == http.StatusOK && w.Body.String() == "alice")
+	})
+
+	t.Run("bad_signature", func(t *testing.T) {
+		other, _ := signed.Generate()
+		r := httptest.NewRequest(http.MethodGet, "/forums", nil)
+		r.Header.Set("Authorization", "Bearer "+string(other.Encode([]byte(`{"uid":1}`))))
+		w := serve(r)
+		assert.True(t, w.Code == http.StatusUnauthorized && lastErr == signed.ErrSign)
+	})
+
+	t.Run("bad_claims", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/forums", nil)
+		r.Header.Set("Authorization", "Bearer "+string(coder.Encode([]byte(`[]`))))
+		w := serve(r)
+		assert.True(t, w.Code == http.StatusUnauthorized && lastErr == ErrInvalidClaims)
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/forums", nil)
+		r.Header.Set("Authorization", "Bearer "+string(coder.Encode([]byte(`{"uid":13}`))))
+		w := serve(r)
+		assert.True(t, w.Code == http.StatusUnauthorized && lastErr == errBanned)
+	})
+}
+
+type sessionClaims struct {
+	signed.Claims
+	UserID int64 `json:"uid"`
+}
+
+func Test_Validator(t *testing.T) {
+	coder, _ := signed.Generate()
+
+	now := time.Unix(1000000, 0)
+
+	var lastErr error
+
+	newHandler := func(v *signed.Validator) http.Handler {
+		return Middleware(&Config{
+			Decoder:   coder,
+			NewClaims: func() interface{} { return new(sessionClaims) },
+			Validator: v,
+			UnauthorizedHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				lastErr = err
+				Unauthorized(w, r, err)
+			},
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	}
+
+	serve := func(h http.Handler, claims *sessionClaims) int {
+		token, _ := coder.EncodeClaims(claims)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+string(token))
+		lastErr = nil
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	t.Run("expired", func(t *testing.T) {
+		claims := &sessionClaims{UserID: 1}
+		claims.SetLifetime(now, time.Hour)
+
+		assert.True(t, serve(newHandler(nil), claims) == http.StatusUnauthorized && lastErr == signed.ErrExpired)
+	})
+
+	t.Run("validator", func(t *testing.T) {
+		h := newHandler(&signed.Validator{
+			Audience:      "api",
+			RequireExpiry: true,
+			Clock:         func() time.Time { return now },
+		})
+
+		claims := &sessionClaims{UserID: 1}
+		claims.Audience = signed.Audience{"api"}
+		claims.SetLifetime(now, time.Hour)
+		assert.True(t, serve(h, claims) == http.StatusOK)
+
+		claims.ExpiresAt = 0
+		assert.True(t, serve(h, claims) == http.StatusUnauthorized && lastErr == signed.ErrMissingExpiry)
+
+		claims.SetLifetime(now, time.Hour)
+		claims.Audience = signed.Audience{"csrf"}
+		assert.True(t, serve(h, claims) == http.StatusUnauthorized && lastErr == signed.ErrAudience)
+	})
+}
+
+func Test_Accessors(t *testing.T) {
+	coder, _ := signed.Generate()
+	token := string(coder.Encode([]byte("raw")))
+
+	var authenticated bool
+	var gotToken, payload string
+
+	handler := Middleware(&Config{Decoder: coder})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authenticated = Authenticated(r.Context())
+		gotToken = GetToken(r.Context())
+		payload = string(GetPayload(r.Context()))
+		assert.True(t, GetClaims(r.Context()) == nil)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.True(t, authenticated && gotToken == token && payload == "raw")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, w.Code == http.StatusUnauthorized)
+}
@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+type Source func(r *http.Request) string
+
+func FromBearer() Source {
+	return func(r *http.Request) string {
+		header := r.Header.Get("Authorization")
+
+		if len(header) > 7 && strings.EqualFold(header[:7], "Bearer ") {
+			return strings.TrimSpace(header[7:])
+		}
+
+		return ""
+	}
+}
+
+func FromCookie(name string) Source {
+	return func(r *http.Request) string {
+		if cookie, err := r.Cookie(name); err == nil {
+			return cookie.Value
+		}
+		return ""
+	}
+}
+
+func FromQuery(name string) Source {
+	return func(r *http.Request) string {
+		return r.URL.Query().Get(name)
+	}
+}
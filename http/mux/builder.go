@@ -6,12 +6,14 @@ func (g *Group) Middleware(fn func(http.Handler) http.Handler) {
 	g.Middlewares = append(g.Middlewares, fn)
 }
 
-func (g *Group) Endpoint(method, path string, handler http.Handler) {
-	g.Endpoints = append(g.Endpoints, &Endpoint{
+func (g *Group) Endpoint(method, path string, handler http.Handler) *Endpoint {
+	e := &Endpoint{
 		Method:  method,
 		Path:    path,
 		Handler: handler,
-	})
+	}
+	g.Endpoints = append(g.Endpoints, e)
+	return e
 }
 
 func (g *Group) Subgroup(fn func(g *Group)) {
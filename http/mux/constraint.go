@@ -0,0 +1,50 @@
+package mux
+
+import (
+	"regexp"
+	"strconv"
+)
+
+type paramConstraint struct {
+	pattern   string
+	matchFunc func(string) bool
+}
+
+var builtinConstraints = map[string]func(string) bool{
+	"int": func(s string) bool {
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	},
+	"uint": func(s string) bool {
+		_, err := strconv.ParseUint(s, 10, 64)
+		return err == nil
+	},
+}
+
+func newParamConstraint(pattern string) (*paramConstraint, bool) {
+	if pattern == "" {
+		return nil, true
+	}
+
+	if fn := builtinConstraints[pattern]; fn != nil {
+		return &paramConstraint{pattern: pattern, matchFunc: fn}, true
+	}
+
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return nil, false
+	}
+
+	return &paramConstraint{pattern: pattern, matchFunc: re.MatchString}, true
+}
+
+func (c *paramConstraint) equal(other *paramConstraint) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return c.pattern == other.pattern
+}
+
+func (c *paramConstraint) match(s string) bool {
+	return c == nil || c.matchFunc(s)
+}
@@ -0,0 +1,68 @@
+package mux
+
+import "context"
+
+type routeContextKey struct{}
+
+type routeContext struct {
+	context.Context
+	path   string
+	params []param
+	buf    [4]param
+}
+
+func newRouteContext(parent context.Context, path string, params []param) *routeContext {
+	rc := &routeContext{
+		Context: parent,
+		path:    path,
+	}
+
+	if len(params) <= len(rc.buf) {
+		rc.params = rc.buf[:len(params)]
+	} else {
+		rc.params = make([]param, len(params))
+	}
+
+	// params are collected from the deepest segment up
+	for i, p := range params {
+		rc.params[len(params)-1-i] = p
+	}
+
+	return rc
+}
+
+func (rc *routeContext) Value(key interface{}) interface{} {
+	switch key {
+	case routeContextKey{}:
+		return rc
+	case PathKey:
+		return rc.path
+	case ParamsKey:
+		if len(rc.params) > 0 {
+			return rc.paramsMap()
+		}
+	}
+	return rc.Context.Value(key)
+}
+
+func (rc *routeContext) paramsMap() map[string]string {
+	m := make(map[string]string, len(rc.params))
+	for _, p := range rc.params {
+		m[p.key] = p.value
+	}
+	return m
+}
+
+func (rc *routeContext) lookup(key string) (string, bool) {
+	for _, p := range rc.params {
+		if p.key == key {
+			return p.value, true
+		}
+	}
+	return "", false
+}
+
+func routeContextFrom(ctx context.Context) *routeContext {
+	rc, _ := ctx.Value(routeContextKey{}).(*routeContext)
+	return rc
+}
@@ -0,0 +1,48 @@
+package mux
+
+import (
+	"context"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_RouteContext(t *testing.T) {
+	type testKey string
+
+	parent := context.WithValue(context.Background(), testKey("x"), "y")
+
+	t.Run("params", func(t *testing.T) {
+		params := []param{{key: "c", value: "3"}, {key: "b", value: "2"}, {key: "a", value: "1"}}
+
+		rc := newRouteContext(parent, "/:a/:b/:c", params)
+
+		assert.DeepEqual(t, rc.params, []param{{key: "a", value: "1"}, {key: "b", value: "2"}, {key: "c", value: "3"}})
+		assert.True(t, rc.Value(PathKey) == "/:a/:b/:c")
+		assert.DeepEqual(t, rc.Value(ParamsKey), map[string]string{"a": "1", "b": "2", "c": "3"})
+		assert.True(t, rc.Value(testKey("x")) == "y")
+		assert.True(t, routeContextFrom(context.WithValue(rc, testKey("z"), 1)) == rc)
+	})
+
+	t.Run("many params", func(t *testing.T) {
+		var params []param
+		for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+			params = append(params, param{key: key, value: key})
+		}
+
+		rc := newRouteContext(parent, "/", params)
+
+		value, ok := rc.lookup("f")
+		assert.True(t, len(rc.params) == 6 && ok && value == "f")
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		outer := newRouteContext(parent, "/outer/:id", []param{{key: "id", value: "1"}})
+		inner := newRouteContext(outer, "/inner", nil)
+
+		assert.True(t, inner.Value(PathKey) == "/inner")
+		assert.DeepEqual(t, inner.Value(ParamsKey), map[string]string{"id": "1"})
+		assert.True(t, routeContextFrom(inner) == inner)
+		assert.True(t, routeContextFrom(parent) == nil)
+	})
+}
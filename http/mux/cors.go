@@ -0,0 +1,175 @@
+package mux
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CORS struct {
+	AllowedOrigins   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+type corsPolicy struct {
+	anyOrigin        bool
+	origins          map[string]bool
+	wildcardOrigins  [][2]string
+	anyHeader        bool
+	headers          map[string]bool
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           string
+}
+
+func newCORSPolicy(cfg *CORS) *corsPolicy {
+	policy := &corsPolicy{
+		origins:          make(map[string]bool),
+		headers:          make(map[string]bool),
+		exposedHeaders:   strings.Join(cfg.ExposedHeaders, ", "),
+		allowCredentials: cfg.AllowCredentials,
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(origin)
+
+		if origin == "*" {
+			policy.anyOrigin = true
+		} else if i := strings.Index(origin, "://*."); i >= 0 {
+			policy.wildcardOrigins = append(policy.wildcardOrigins, [2]string{origin[:i+3], origin[i+4:]})
+		} else {
+			policy.origins[origin] = true
+		}
+	}
+
+	for _, header := range cfg.AllowedHeaders {
+		if header == "*" {
+			policy.anyHeader = true
+		} else {
+			policy.headers[http.CanonicalHeaderKey(header)] = true
+		}
+	}
+
+	if cfg.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(int(cfg.MaxAge / time.Second))
+	}
+
+	return policy
+}
+
+func (policy *corsPolicy) allowOrigin(origin string) bool {
+	if policy.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+
+	if policy.origins[origin] {
+		return true
+	}
+
+	for _, wildcard := range policy.wildcardOrigins {
+		scheme, suffix := wildcard[0], wildcard[1]
+		if len(origin) > len(scheme)+len(suffix) && strings.HasPrefix(origin, scheme) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (policy *corsPolicy) allowHeaders(requested string) (string, bool) {
+	if requested == "" || policy.anyHeader {
+		return requested, true
+	}
+
+	for _, header := range strings.Split(requested, ",") {
+		if !policy.headers[http.CanonicalHeaderKey(strings.TrimSpace(header))] {
+			return "", false
+		}
+	}
+
+	return requested, true
+}
+
+func (policy *corsPolicy) setOriginHeaders(h http.Header, origin string) {
+	if policy.anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+		h.Add("Vary", "Origin")
+	}
+
+	if policy.allowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+}
+
+func (policy *corsPolicy) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if policy.allowOrigin(origin) {
+				policy.setOriginHeaders(w.Header(), origin)
+
+				if policy.exposedHeaders != "" {
+					w.Header().Set("Access-Control-Expose-Headers", policy.exposedHeaders)
+				}
+			} else if !policy.anyOrigin {
+				w.Header().Add("Vary", "Origin")
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (policy *corsPolicy) servePreflight(w http.ResponseWriter, r *http.Request, methods []string) {
+	h := w.Header()
+
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	origin := r.Header.Get("Origin")
+
+	headers, ok := policy.allowHeaders(r.Header.Get("Access-Control-Request-Headers"))
+
+	if ok && policy.allowOrigin(origin) {
+		policy.setOriginHeaders(h, origin)
+
+		h.Set("Access-Control-Allow-Methods", strings.Join(methods, ", "))
+
+		if headers != "" {
+			h.Set("Access-Control-Allow-Headers", headers)
+		}
+		if policy.maxAge != "" {
+			h.Set("Access-Control-Max-Age", policy.maxAge)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+func (hr *Router) preflightPolicy(r *http.Request, path string) *corsPolicy {
+	if len(hr.corsPolicies) == 0 || !isPreflight(r) {
+		return nil
+	}
+
+	value, _ := hr.match(r.Host, r.Header.Get("Access-Control-Request-Method"), path, nil)
+
+	if value == nil {
+		return nil
+	}
+
+	return hr.corsPolicies[value.endpoint]
+}
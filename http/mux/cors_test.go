@@ -0,0 +1,174 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_CORS(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	g := new(Group)
+
+	g.Subgroup(func(g *Group) {
+		g.CORS = &CORS{
+			AllowedOrigins:   []string{"https://fantlab.ru", "https://*.fantlab.ru"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization"},
+			ExposedHeaders:   []string{"X-Total"},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		}
+
+		g.Endpoint(http.MethodGet, "/works/:id", emptyHandler)
+		g.Endpoint(http.MethodPut, "/works/:id", emptyHandler)
+
+		g.Subgroup(func(g *Group) {
+			g.Middleware(checkTestAuthMiddleware)
+
+			g.Endpoint(http.MethodDelete, "/works/:id", emptyHandler)
+		})
+	})
+
+	g.Subgroup(func(g *Group) {
+		g.CORS = &CORS{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}}
+
+		g.Endpoint(http.MethodGet, "/public", emptyHandler)
+		g.Endpoint(http.MethodOptions, "/public", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("custom"))
+		}))
+		g.Endpoint(http.MethodPost, "/public/feedback", emptyHandler)
+	})
+
+	g.Endpoint(http.MethodGet, "/private", emptyHandler)
+
+	router, errs := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	assert.True(t, errs == nil)
+
+	preflight := func(path, origin, method, headers string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodOptions, path, nil)
+		r.Header.Set("Origin", origin)
+		r.Header.Set("Access-Control-Request-Method", method)
+		if headers != "" {
+			r.Header.Set("Access-Control-Request-Headers", headers)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	t.Run("preflight", func(t *testing.T) {
+		rr := preflight("/works/1", "https://api.fantlab.ru", http.MethodDelete, "content-type, authorization")
+		h := rr.Header()
+
+		assert.True(t, rr.Code == http.StatusNoContent)
+		assert.True(t, h.Get("Access-Control-Allow-Origin") == "https://api.fantlab.ru")
+		assert.True(t, h.Get("Access-Control-Allow-Methods") == "DELETE, GET, PUT")
+		assert.True(t, h.Get("Access-Control-Allow-Headers") == "content-type, authorization")
+		assert.True(t, h.Get("Access-Control-Allow-Credentials") == "true")
+		assert.True(t, h.Get("Access-Control-Max-Age") == "600")
+	})
+
+	t.Run("preflight rejected", func(t *testing.T) {
+		for _, rr := range []*httptest.ResponseRecorder{
+			preflight("/works/1", "https://evil.ru", http.MethodGet, ""),
+			preflight("/works/1", "https://fantlab.ru.evil.ru", http.MethodGet, ""),
+			preflight("/works/1", "https://fantlab.ru", http.MethodGet, "X-Custom"),
+		} {
+			assert.True(t, rr.Code == http.StatusNoContent)
+			assert.True(t, rr.Header().Get("Access-Control-Allow-Origin") == "")
+		}
+	})
+
+	t.Run("preflight without policy", func(t *testing.T) {
+		{
+			rr := preflight("/private", "https://fantlab.ru", http.MethodGet, "")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+		}
+		{
+			rr := preflight("/works/1", "https://fantlab.ru", http.MethodPost, "")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+		}
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		rr := preflight("/public", "https://example.com", http.MethodGet, "")
+		assert.True(t, rr.Body.String() == "custom")
+	})
+
+	t.Run("wildcard", func(t *testing.T) {
+		rr := preflight("/public/feedback", "https://example.com", http.MethodPost, "X-Anything")
+		assert.True(t, rr.Header().Get("Access-Control-Allow-Origin") == "*")
+		assert.True(t, rr.Header().Get("Access-Control-Allow-Headers") == "X-Anything")
+		assert.True(t, rr.Header().Get("Access-Control-Allow-Credentials") == "")
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		{
+			r := httptest.NewRequest(http.MethodGet, "/works/1", nil)
+			r.Header.Set("Origin", "https://fantlab.ru")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, r)
+
+			assert.True(t, rr.Header().Get("Access-Control-Allow-Origin") == "https://fantlab.ru")
+			assert.True(t, rr.Header().Get("Access-Control-Expose-Headers") == "X-Total")
+			assert.True(t, rr.Header().Get("Vary") == "Origin")
+		}
+		{
+			r := httptest.NewRequest(http.MethodDelete, "/works/1", nil)
+			r.Header.Set("Origin", "https://fantlab.ru")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, r)
+
+			assert.True(t, rr.Code == http.StatusBadRequest)
+			assert.True(t, rr.Header().Get("Access-Control-Allow-Origin") == "https://fantlab.ru")
+		}
+		{
+			r := httptest.NewRequest(http.MethodGet, "/works/1", nil)
+			r.Header.Set("Origin", "https://evil.ru")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, r)
+
+			assert.True(t, rr.Header().Get("Access-Control-Allow-Origin") == "")
+		}
+		{
+			r := httptest.NewRequest(http.MethodGet, "/private", nil)
+			r.Header.Set("Origin", "https://fantlab.ru")
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, r)
+
+			assert.True(t, rr.Header().Get("Access-Control-Allow-Origin") == "")
+		}
+	})
+}
+
+func Test_CORSCredentials(t *testing.T) {
+	g := new(Group)
+
+	g.Subgroup(func(g *Group) {
+		g.CORS = &CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}
+
+		g.Endpoint(http.MethodGet, "/works", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	})
+
+	router, errs := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	assert.True(t, len(errs) == 1 && errs[0].Err == ErrInvalidCORS)
+
+	rr := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/works", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	router.ServeHTTP(rr, r)
+
+	assert.True(t, rr.Code == http.StatusNotFound && rr.Header().Get("Access-Control-Allow-Origin") == "")
+}
@@ -0,0 +1,224 @@
+package csrf
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+	"github.com/FantLab/go-kit/http/mux/session"
+)
+
+var (
+	ErrNoMiddleware    = errors.New("csrf: middleware is not installed")
+	ErrMissingToken    = errors.New("csrf: missing token")
+	ErrInvalidToken    = errors.New("csrf: invalid token")
+	ErrSessionMismatch = errors.New("csrf: token belongs to another session")
+)
+
+const (
+	DefaultHeader     = "X-CSRF-Token"
+	DefaultFormField  = "csrf_token"
+	DefaultCookieName = "csrf"
+	DefaultMaxAge     = 12 * time.Hour
+)
+
+// keeps csrf tokens from being accepted as sessions or bearer tokens signed by the same coder
+const audience = "csrf"
+
+type Config struct {
+	Coder     *signed.Coder
+	Header    string
+	FormField string
+	// double-submit cookie mode, otherwise tokens are bound to the session.Middleware session
+	Stateless  bool
+	CookieName string
+	CookiePath string
+	Insecure   bool
+	MaxAge     time.Duration
+	// skips verification, e.g. for JSON API routes nested in a protected group
+	Exempt       func(r *http.Request) bool
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	Clock        func() time.Time
+}
+
+type state struct {
+	cfg         *Config
+	ctx         context.Context
+	cookieToken string
+}
+
+type stateKey struct{}
+
+func Middleware(cfg *Config) mux.Middleware {
+	c := *cfg
+
+	if c.Header == "" {
+		c.Header = DefaultHeader
+	}
+	if c.FormField == "" {
+		c.FormField = DefaultFormField
+	}
+	if c.CookieName == "" {
+		c.CookieName = DefaultCookieName
+	}
+	if c.CookiePath == "" {
+		c.CookiePath = "/"
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = DefaultMaxAge
+	}
+	if c.ErrorHandler == nil {
+		c.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	}
+	if c.Clock == nil {
+		c.Clock = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			st := &state{cfg: &c}
+
+			if c.Stateless {
+				st.cookieToken = st.cookie(w, r)
+			}
+
+			st.ctx = context.WithValue(r.Context(), stateKey{}, st)
+			r = r.WithContext(st.ctx)
+
+			if !safeMethod(r.Method) && (c.Exempt == nil || !c.Exempt(r)) {
+				if err := st.verify(r); err != nil {
+					c.ErrorHandler(w, r, err)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// for forms and pages, every call in the session mode returns a fresh token
+func Token(ctx context.Context) (string, error) {
+	st, ok := ctx.Value(stateKey{}).(*state)
+	if !ok {
+		return "", ErrNoMiddleware
+	}
+	if st.cfg.Stateless {
+		return st.cookieToken, nil
+	}
+	s, err := session.Load(st.ctx)
+	if err != nil {
+		return "", err
+	}
+	s.Touch()
+	return st.issue(s.ID())
+}
+
+func (st *state) issue(subject string) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	claims := signed.Claims{
+		Subject:  subject,
+		Audience: signed.Audience{audience},
+		ID:       base64.RawURLEncoding.EncodeToString(nonce),
+	}
+	claims.SetLifetime(st.cfg.Clock(), st.cfg.MaxAge)
+	token, err := st.cfg.Coder.EncodeClaims(&claims)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
+
+func (st *state) decode(token string) (*signed.Claims, error) {
+	var claims signed.Claims
+	err := st.cfg.Coder.DecodeClaims([]byte(token), &claims, &signed.Validator{
+		Audience:      audience,
+		RequireExpiry: true,
+		Clock:         st.cfg.Clock,
+	})
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// keeps a valid double-submit cookie or issues a new one
+func (st *state) cookie(w http.ResponseWriter, r *http.Request) string {
+	if cookie, err := r.Cookie(st.cfg.CookieName); err == nil {
+		if _, err := st.decode(cookie.Value); err == nil {
+			return cookie.Value
+		}
+	}
+
+	token, err := st.issue("")
+	if err != nil {
+		return ""
+	}
+
+	// readable by scripts that copy it into the header
+	http.SetCookie(w, &http.Cookie{
+		Name:     st.cfg.CookieName,
+		Value:    token,
+		Path:     st.cfg.CookiePath,
+		MaxAge:   int(st.cfg.MaxAge / time.Second),
+		Secure:   !st.cfg.Insecure,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return token
+}
+
+func (st *state) verify(r *http.Request) error {
+	token := r.Header.Get(st.cfg.Header)
+
+	if token == "" {
+		token = r.PostFormValue(st.cfg.FormField)
+	}
+	if token == "" {
+		return ErrMissingToken
+	}
+
+	if st.cfg.Stateless {
+		cookie, err := r.Cookie(st.cfg.CookieName)
+		if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(token)) != 1 {
+			return ErrInvalidToken
+		}
+		_, err = st.decode(token)
+		return err
+	}
+
+	claims, err := st.decode(token)
+	if err != nil {
+		return err
+	}
+
+	s, err := session.Load(st.ctx)
+	if err != nil {
+		return err
+	}
+
+	if s.IsNew() || subtle.ConstantTimeCompare([]byte(claims.Subject), []byte(s.ID())) != 1 {
+		return ErrSessionMismatch
+	}
+
+	return nil
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+package csrf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+	"github.com/FantLab/go-kit/http/mux/auth"
+	"github.com/FantLab/go-kit/http/mux/session"
+)
+
+func Test_SessionTokens(t *testing.T) {
+	coder, _ := signed.Generate()
+
+	var lastErr error
+
+	rootGroup := new(mux.Group)
+
+	rootGroup.Middleware(session.Middleware(&session.Config{Codec: session.Signed(coder)}))
+
+	rootGroup.Subgroup(func(g *mux.Group) {
+		g.Middleware(Middleware(&Config{
+			Coder: coder,
+			Exempt: func(r *http.Request) bool {
+				return strings.HasPrefix(mux.Path(r), "/forum/api/")
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				lastErr = err
+				w.WriteHeader(http.StatusForbidden)
+			},
+		}))
+
+		g.Endpoint(http.MethodGet, "/forum/new", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := Token(r.Context())
+			assert.True(t, err == nil)
+			_, _ = w.Write([]byte(token))
+		}))
+		g.Endpoint(http.MethodPost, "/forum/topics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+		g.Endpoint(http.MethodPost, "/forum/api/topics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		}))
+	})
+
+	rootGroup.Endpoint(http.MethodPost, "/api/works", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       rootGroup,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		lastErr = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(httptest.NewRequest(http.MethodGet, "/forum/new", nil))
+	token := w.Body.String()
+	cookies := w.Result().Cookies()
+
+	post := func(path string, cookies []*http.Cookie, header, field string) int {
+		form := url.Values{}
+		if field != "" {
+			form.Set(DefaultFormField, field)
+		}
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if header != "" {
+			r.Header.Set(DefaultHeader, header)
+		}
+		for _, cookie := range cookies {
+			r.AddCookie(cookie)
+		}
+		return serve(r).Code
+	}
+
+	t.Run("issued_with_session", func(t *testing.T) {
+		assert.True(t, token != "" && len(cookies) == 1)
+	})
+
+	t.Run("header", func(t *testing.T) {
+		assert.True(t, post("/forum/topics", cookies, token, "") == http.StatusCreated)
+	})
+
+	t.Run("form_field", func(t *testing.T) {
+		assert.True(t, post("/forum/topics", cookies, "", token) == http.StatusCreated)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		assert.True(t, post("/forum/topics", cookies, "", "") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrMissingToken)
+	})
+
+	t.Run("forged", func(t *testing.T) {
+		other, _ := signed.Generate()
+		forged, _ := other.EncodeClaims(&signed.Claims{Subject: "x", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		assert.True(t, post("/forum/topics", cookies, string(forged), "") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidToken)
+	})
+
+	t.Run("not_a_token", func(t *testing.T) {
+		bearer, _ := coder.EncodeClaims(&signed.Claims{Subject: "x", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+		assert.True(t, post("/forum/topics", cookies, string(bearer), "") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidToken)
+		assert.True(t, post("/forum/topics", cookies, cookies[0].Value, "") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidToken)
+	})
+
+	t.Run("not_a_session", func(t *testing.T) {
+		var authErr error
+
+		authenticated := auth.Middleware(&auth.Config{
+			Decoder:   coder,
+			NewClaims: func() interface{} { return new(signed.Claims) },
+			UnauthorizedHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				authErr = err
+				auth.Unauthorized(w, r, err)
+			},
+		})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		authenticated.ServeHTTP(w, r)
+		assert.True(t, w.Code == http.StatusUnauthorized && authErr == signed.ErrAudience)
+
+		var isNew bool
+
+		sessions := session.Middleware(&session.Config{Codec: session.Signed(coder)})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, _ := session.Load(r.Context())
+			isNew = s.IsNew()
+		}))
+
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(&http.Cookie{Name: session.DefaultCookieName, Value: token})
+		sessions.ServeHTTP(httptest.NewRecorder(), r)
+		assert.True(t, isNew)
+	})
+
+	t.Run("other_session", func(t *testing.T) {
+		assert.True(t, post("/forum/topics", nil, token, "") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrSessionMismatch)
+
+		w := serve(httptest.NewRequest(http.MethodGet, "/forum/new", nil))
+		assert.True(t, post("/forum/topics", w.Result().Cookies(), token, "") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrSessionMismatch)
+	})
+
+	t.Run("exempt", func(t *testing.T) {
+		assert.True(t, post("/forum/api/topics", nil, "", "") == http.StatusCreated)
+		assert.True(t, post("/api/works", nil, "", "") == http.StatusCreated)
+	})
+}
+
+func Test_DoubleSubmit(t *testing.T) {
+	coder, _ := signed.Generate()
+
+	now := time.Unix(1000000, 0)
+
+	handler := Middleware(&Config{
+		Coder:     coder,
+		Stateless: true,
+		MaxAge:    time.Hour,
+		Clock:     func() time.Time { return now },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := Token(r.Context())
+		_, _ = w.Write([]byte(token))
+	}))
+
+	serve := func(method string, cookie *http.Cookie, header string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "/", nil)
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+		if header != "" {
+			r.Header.Set(DefaultHeader, header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := serve(http.MethodGet, nil, "")
+	cookie := w.Result().Cookies()[0]
+
+	t.Run("cookie", func(t *testing.T) {
+		assert.True(t, cookie.Name == DefaultCookieName && cookie.Value == w.Body.String())
+		assert.True(t, !cookie.HttpOnly && cookie.Secure && cookie.MaxAge == 3600)
+
+		w := serve(http.MethodGet, cookie, "")
+		assert.True(t, len(w.Result().Cookies()) == 0 && w.Body.String() == cookie.Value)
+	})
+
+	t.Run("positive", func(t *testing.T) {
+		assert.True(t, serve(http.MethodPost, cookie, cookie.Value).Code == http.StatusOK)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		other := serve(http.MethodGet, nil, "").Result().Cookies()[0]
+		assert.True(t, serve(http.MethodPost, cookie, other.Value).Code == http.StatusForbidden)
+		assert.True(t, serve(http.MethodPost, nil, cookie.Value).Code == http.StatusForbidden)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		fake := &http.Cookie{Name: DefaultCookieName, Value: "fake"}
+		assert.True(t, serve(http.MethodPost, fake, "fake").Code == http.StatusForbidden)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		w := serve(http.MethodPost, cookie, cookie.Value)
+		assert.True(t, w.Code == http.StatusForbidden)
+		assert.True(t, len(w.Result().Cookies()) == 1)
+	})
+}
+
+func Test_NoMiddleware(t *testing.T) {
+	_, err := Token(context.Background())
+	assert.True(t, err == ErrNoMiddleware)
+}
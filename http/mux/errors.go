@@ -0,0 +1,76 @@
+package mux
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidMethod  = errors.New("mux: invalid method")
+	ErrNilHandler     = errors.New("mux: handler must not be nil")
+	ErrInvalidPath    = errors.New("mux: invalid path")
+	ErrInvalidHost    = errors.New("mux: invalid host pattern")
+	ErrDuplicateRoute = errors.New("mux: duplicate route")
+	ErrAmbiguousParam = errors.New("mux: ambiguous parameter name")
+	ErrDuplicateName  = errors.New("mux: duplicate route name")
+	ErrRouteNotFound  = errors.New("mux: route not found")
+	ErrMissingParam   = errors.New("mux: missing path parameter")
+	ErrInvalidParam   = errors.New("mux: invalid path parameter")
+	ErrOddParams      = errors.New("mux: odd number of path parameter arguments")
+	ErrInvalidCORS    = errors.New("mux: credentials can't be allowed for any origin")
+)
+
+type RouteError struct {
+	Err      error
+	Endpoint *Endpoint
+	Conflict *Endpoint
+}
+
+func (e *RouteError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+	if e.Endpoint != nil {
+		sb.WriteString(": ")
+		sb.WriteString(e.Endpoint.String())
+	}
+	if e.Conflict != nil {
+		sb.WriteString(" (conflicts with ")
+		sb.WriteString(e.Conflict.String())
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+func (e *RouteError) Unwrap() error {
+	return e.Err
+}
+
+type RouteErrors []*RouteError
+
+func (errs RouteErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (errs RouteErrors) Endpoints() []*Endpoint {
+	var endpoints []*Endpoint
+	for _, err := range errs {
+		if err.Endpoint != nil && (len(endpoints) == 0 || endpoints[len(endpoints)-1] != err.Endpoint) {
+			endpoints = append(endpoints, err.Endpoint)
+		}
+	}
+	return endpoints
+}
+
+func (errs RouteErrors) Filter(target error) RouteErrors {
+	var filtered RouteErrors
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			filtered = append(filtered, err)
+		}
+	}
+	return filtered
+}
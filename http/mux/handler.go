@@ -1,8 +1,10 @@
 package mux
 
 import (
-	"context"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
 )
 
 type ContextKey string
@@ -12,71 +14,288 @@ const (
 	PathKey   = ContextKey("path")
 )
 
-type httpRouter struct {
-	tree            map[string]*trie
-	notFoundHandler http.Handler
+type Router struct {
+	hosts                   map[string]methodTrees
+	wildcardHosts           []string
+	routes                  map[string]*namedRoute
+	table                   []Route
+	prefix                  string
+	segmentValidator        func(string) bool
+	notFoundHandler         http.Handler
+	methodNotAllowedHandler http.Handler
+	autoHead                bool
+	autoOptions             bool
+	pathPolicy              PathPolicy
+	redirectCaseInsensitive bool
+	corsPolicies            map[*Endpoint]*corsPolicy
 }
 
-func (hr *httpRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	trie := hr.tree[r.Method]
+func (hr *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
 
-	if trie == nil {
-		hr.notFoundHandler.ServeHTTP(w, r)
+	if hr.pathPolicy != PathLenient {
+		path = cleanPath(path)
+	}
+
+	params := paramsPool.Get().(*[]param)
+	defer releaseParams(params)
+
+	if value, head := hr.match(r.Host, r.Method, path, params); value != nil {
+		switch canonical := value.canonicalPath(path); {
+		case hr.pathPolicy == PathLenient || canonical == r.URL.Path:
+			if head {
+				w = headResponseWriter{w}
+			}
+			serveRoute(value, *params, w, r)
+			return
+		case hr.pathPolicy == PathRedirect:
+			redirect(w, r, canonical)
+			return
+		}
+	}
+
+	if policy := hr.preflightPolicy(r, path); policy != nil {
+		policy.servePreflight(w, r, hr.allowedMethods(r.Host, path, r.URL.Path))
 		return
 	}
 
-	value, params := trie.handlerForPath(r.URL.Path)
+	if hr.redirectCaseInsensitive {
+		if canonical := hr.lookupFold(r.Host, r.Method, path); hr.foldRedirect(canonical, r.URL.Path) {
+			redirect(w, r, canonical)
+			return
+		}
+	}
+
+	methods := hr.allowedMethods(r.Host, path, r.URL.Path)
 
-	if value == nil {
+	if len(methods) == 0 {
 		hr.notFoundHandler.ServeHTTP(w, r)
 		return
 	}
 
-	r = r.WithContext(context.WithValue(r.Context(), PathKey, value.path))
-	if len(params) > 0 {
-		r = r.WithContext(context.WithValue(r.Context(), ParamsKey, params))
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+
+	if r.Method == http.MethodOptions && hr.autoOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
 	}
 
-	value.handler.ServeHTTP(w, r)
+	hr.methodNotAllowedHandler.ServeHTTP(w, r)
+}
+
+var paramsPool = sync.Pool{
+	New: func() interface{} {
+		params := make([]param, 0, 8)
+		return &params
+	},
+}
+
+func releaseParams(params *[]param) {
+	*params = (*params)[:0]
+	paramsPool.Put(params)
+}
+
+func (hr *Router) match(host, method, path string, params *[]param) (*pathHandler, bool) {
+	if value := hr.lookup(host, method, path, params); value != nil {
+		return value, false
+	}
+
+	if method == http.MethodHead && hr.autoHead {
+		if value := hr.lookup(host, http.MethodGet, path, params); value != nil {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+func (hr *Router) lookup(host, method, path string, params *[]param) (value *pathHandler) {
+	hr.forEachHost(host, func(trees methodTrees) bool {
+		if trie := trees[method]; trie != nil {
+			value = trie.match(path, params)
+		}
+		return value != nil
+	})
+
+	return
+}
+
+func (hr *Router) allowedMethods(host, path, requestPath string) []string {
+	allowed := make(map[string]bool)
+
+	hr.forEachHost(host, func(trees methodTrees) bool {
+		for method, trie := range trees {
+			if value := trie.match(path, nil); value != nil {
+				allowed[method] = hr.pathPolicy != PathStrict || value.canonicalPath(path) == requestPath
+			}
+		}
+		return false
+	})
+
+	for method, ok := range allowed {
+		if !ok {
+			delete(allowed, method)
+		}
+	}
+
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	if hr.autoHead && allowed[http.MethodGet] {
+		allowed[http.MethodHead] = true
+	}
+	if hr.autoOptions {
+		allowed[http.MethodOptions] = true
+	}
+
+	methods := make([]string, 0, len(allowed))
+
+	for method := range allowed {
+		methods = append(methods, method)
+	}
+
+	sort.Strings(methods)
+
+	return methods
+}
+
+func serveRoute(value *pathHandler, params []param, w http.ResponseWriter, r *http.Request) {
+	value.handler.ServeHTTP(w, r.WithContext(newRouteContext(r.Context(), value.path, params)))
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
+
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w headResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
 }
 
 type Config struct {
-	RootGroup            *Group
-	NotFoundHandler      http.Handler
-	CommonPrefix         string
-	PathSegmentValidator func(string) bool
-	GlobalMiddlewares    []Middleware
+	RootGroup               *Group
+	NotFoundHandler         http.Handler
+	MethodNotAllowedHandler http.Handler
+	CommonPrefix            string
+	PathSegmentValidator    func(string) bool
+	GlobalMiddlewares       []Middleware
+	AutoHead                bool
+	AutoOptions             bool
+	PathPolicy              PathPolicy
+	RedirectCaseInsensitive bool
+	Strict                  bool
 }
 
-func NewRouter(cfg *Config) (http.Handler, []*Endpoint) {
+func NewRouter(cfg *Config) (*Router, RouteErrors) {
 	if cfg == nil || cfg.NotFoundHandler == nil || cfg.RootGroup == nil {
 		return nil, nil
 	}
 
-	router := &httpRouter{
-		tree:            make(map[string]*trie),
-		notFoundHandler: cfg.NotFoundHandler,
+	router := &Router{
+		hosts:                   make(map[string]methodTrees),
+		routes:                  make(map[string]*namedRoute),
+		prefix:                  cfg.CommonPrefix,
+		segmentValidator:        cfg.PathSegmentValidator,
+		notFoundHandler:         cfg.NotFoundHandler,
+		methodNotAllowedHandler: cfg.MethodNotAllowedHandler,
+		autoHead:                cfg.AutoHead,
+		autoOptions:             cfg.AutoOptions,
+		pathPolicy:              cfg.PathPolicy,
+		redirectCaseInsensitive: cfg.RedirectCaseInsensitive,
 	}
 
-	var badEndpoints []*Endpoint
+	if router.methodNotAllowedHandler == nil {
+		router.methodNotAllowedHandler = http.HandlerFunc(methodNotAllowed)
+	}
+
+	var errs RouteErrors
+
+	walkGroup(cfg.RootGroup, groupScope{middlewares: cfg.GlobalMiddlewares}, func(scope groupScope, e *Endpoint) {
+		if !httpMethods[e.Method] {
+			errs = append(errs, &RouteError{Err: ErrInvalidMethod, Endpoint: e})
+			return
+		}
+
+		host, ok := parseHostPattern(scope.host)
+
+		if !ok {
+			errs = append(errs, &RouteError{Err: ErrInvalidHost, Endpoint: e})
+			return
+		}
+
+		if scope.cors != nil && scope.cors.anyOrigin && scope.cors.allowCredentials {
+			errs = append(errs, &RouteError{Err: ErrInvalidCORS, Endpoint: e})
+			return
+		}
+
+		trees := router.hosts[host]
+
+		if trees == nil {
+			trees = make(methodTrees)
 
-	walkGroup(cfg.RootGroup, cfg.GlobalMiddlewares, func(mws []Middleware, e *Endpoint) {
-		if httpMethods[e.Method] {
-			tree := router.tree[e.Method]
+			router.hosts[host] = trees
 
-			if tree == nil {
-				tree = newPathTrie(cfg.CommonPrefix, cfg.PathSegmentValidator)
+			if isWildcardHost(host) {
+				router.addWildcardHost(host)
+			}
+		}
+
+		tree := trees[e.Method]
+
+		if tree == nil {
+			tree = newPathTrie(cfg.CommonPrefix, cfg.PathSegmentValidator)
+
+			trees[e.Method] = tree
+		}
 
-				router.tree[e.Method] = tree
+		path := e.Path
+
+		if scope.prefix != "" {
+			path = joinPath(scope.prefix, e.Path)
+
+			if strings.HasSuffix(e.Path, "/") && strings.Trim(e.Path, "/") != "" {
+				path += "/"
 			}
+		}
+
+		mws := append(scope.middlewares[:len(scope.middlewares):len(scope.middlewares)], e.Middlewares...)
+
+		if scope.cors != nil {
+			policy := scope.cors
 
-			if tree.insertPathHandler(e.Path, chainHandler(e.Handler, mws...)) {
-				return
+			if router.corsPolicies == nil {
+				router.corsPolicies = make(map[*Endpoint]*corsPolicy)
 			}
+
+			router.corsPolicies[e] = policy
+
+			mws = append([]Middleware{policy.middleware}, mws...)
+		}
+
+		insertErrs := tree.insertPathHandler(path, chainHandler(e.Handler, mws...), e)
+
+		errs = append(errs, insertErrs...)
+
+		if insertErrs.Filter(ErrInvalidPath) != nil || insertErrs.Filter(ErrNilHandler) != nil {
+			return
 		}
 
-		badEndpoints = append(badEndpoints, e)
+		router.addRoute(e, host, path, len(mws))
+
+		if e.Name != "" {
+			if err := router.addNamedRoute(e, path); err != nil {
+				errs = append(errs, err)
+			}
+		}
 	})
 
-	return router, badEndpoints
+	if cfg.Strict && len(errs) > 0 {
+		return nil, errs
+	}
+
+	return router, errs
 }
@@ -2,6 +2,7 @@ package mux
 
 import (
 	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -13,19 +14,54 @@ import (
 func Test_Router(t *testing.T) {
 	t.Run("bad config", func(t *testing.T) {
 		{
-			handler, badEndpoints := NewRouter(nil)
-			assert.True(t, handler == nil && badEndpoints == nil)
+			handler, errs := NewRouter(nil)
+			assert.True(t, handler == nil && errs == nil)
 		}
 		{
-			handler, badEndpoints := NewRouter(&Config{})
-			assert.True(t, handler == nil && badEndpoints == nil)
+			handler, errs := NewRouter(&Config{})
+			assert.True(t, handler == nil && errs == nil)
 		}
 	})
 
-	router, invalidEndpoints := makeTestRouter()
+	router, errs := makeTestRouter(false)
 
-	t.Run("invalid endpoints", func(t *testing.T) {
-		assert.True(t, len(invalidEndpoints) == 1 && invalidEndpoints[0].Path == "/blog_topics/:id/message")
+	t.Run("diagnostics", func(t *testing.T) {
+		{
+			invalid := errs.Filter(ErrInvalidPath)
+			assert.True(t, len(invalid) == 1 && invalid[0].Endpoint.Path == "/blog_topics/:id/message")
+		}
+		{
+			ambiguous := errs.Filter(ErrAmbiguousParam)
+			assert.True(t, len(ambiguous) == 1)
+			assert.True(t, ambiguous[0].Endpoint.Path == "/work/:work_id_2/info")
+			assert.True(t, ambiguous[0].Conflict.Path == "/work/:work_id/info")
+		}
+		{
+			duplicates := errs.Filter(ErrDuplicateRoute)
+			assert.True(t, len(duplicates) == 1)
+			assert.True(t, duplicates[0].Endpoint.Path == "/work/:work_id_2/info")
+			assert.True(t, duplicates[0].Conflict.Path == "/work/:work_id/info")
+			assert.True(t, duplicates[0].Error() == "mux: duplicate route: GET /work/:work_id_2/info (conflicts with GET /work/:work_id/info)")
+		}
+		assert.True(t, len(errs) == 3)
+		assert.True(t, len(errs.Endpoints()) == 2)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		handler, strictErrs := makeTestRouter(true)
+		assert.True(t, handler == nil)
+		assert.DeepEqual(t, strictErrs.Error(), errs.Error())
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		g := new(Group)
+		g.Endpoint("PURGE", "/x", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		_, errs := NewRouter(&Config{
+			RootGroup:       g,
+			NotFoundHandler: http.NotFoundHandler(),
+		})
+		assert.True(t, len(errs) == 1 && errors.Is(errs[0], ErrInvalidMethod))
 	})
 
 	ts := httptest.NewServer(router)
@@ -55,15 +91,11 @@ func Test_Router(t *testing.T) {
 
 	t.Run("fail", func(t *testing.T) {
 		{
-			resp := sendTestRequest(http.MethodGet, ts.URL+"/v1/auth/login", false)
+			resp := sendTestRequest(http.MethodGet, ts.URL+"/v1/auth/logout", false)
 			assert.True(t, resp == "not found")
 		}
 		{
-			resp := sendTestRequest(http.MethodDelete, ts.URL+"/v1/auth/login", false)
-			assert.True(t, resp == "not found")
-		}
-		{
-			resp := sendTestRequest(http.MethodDelete, ts.URL+"/v1/forums/qwe", false)
+			resp := sendTestRequest(http.MethodDelete, ts.URL+"/v1/forums/qwe/x", false)
 			assert.True(t, resp == "not found")
 		}
 		{
@@ -73,6 +105,137 @@ func Test_Router(t *testing.T) {
 	})
 }
 
+func Test_MethodNotAllowed(t *testing.T) {
+	router, _ := makeTestRouter(false)
+
+	ts := httptest.NewServer(router)
+	defer ts.Close()
+
+	t.Run("single method", func(t *testing.T) {
+		res, body := doTestRequest(http.MethodDelete, ts.URL+"/v1/auth/login", false)
+		assert.True(t, body == "method not allowed")
+		assert.True(t, res.StatusCode == http.StatusMethodNotAllowed)
+		assert.True(t, res.Header.Get("Allow") == "POST")
+	})
+
+	t.Run("param routes", func(t *testing.T) {
+		res, body := doTestRequest(http.MethodPost, ts.URL+"/v1/topics/kek", false)
+		assert.True(t, body == "method not allowed")
+		assert.True(t, res.Header.Get("Allow") == "GET")
+
+		res, body = doTestRequest(http.MethodDelete, ts.URL+"/v1/topics/kek/subscription", false)
+		assert.True(t, body == "method not allowed")
+		assert.True(t, res.Header.Get("Allow") == "PUT")
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		res, body := doTestRequest("PURGE", ts.URL+"/v1/forums/qwe", false)
+		assert.True(t, body == "method not allowed")
+		assert.True(t, res.Header.Get("Allow") == "GET")
+	})
+
+	t.Run("default handler", func(t *testing.T) {
+		g := new(Group)
+		g.Endpoint(http.MethodGet, "/x", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		g.Endpoint(http.MethodPost, "/x", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		router, _ := NewRouter(&Config{
+			RootGroup:       g,
+			NotFoundHandler: http.NotFoundHandler(),
+		})
+
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/x", nil))
+		assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+		assert.True(t, rr.Header().Get("Allow") == "GET, POST")
+
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodDelete, "/y", nil))
+		assert.True(t, rr.Code == http.StatusNotFound)
+		assert.True(t, rr.Header().Get("Allow") == "")
+	})
+}
+
+func Test_AutoMethods(t *testing.T) {
+	makeRouter := func(autoHead, autoOptions bool) http.Handler {
+		g := new(Group)
+		g.Endpoint(http.MethodGet, "/x", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Test", "get")
+			_, _ = w.Write([]byte("get"))
+		}))
+		g.Endpoint(http.MethodPost, "/x", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		g.Endpoint(http.MethodPost, "/y", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		g.Endpoint(http.MethodOptions, "/y", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("custom options"))
+		}))
+
+		router, _ := NewRouter(&Config{
+			RootGroup:       g,
+			NotFoundHandler: http.NotFoundHandler(),
+			AutoHead:        autoHead,
+			AutoOptions:     autoOptions,
+		})
+
+		return router
+	}
+
+	serve := func(router http.Handler, method, path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, path, nil))
+		return rr
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		router := makeRouter(false, false)
+		{
+			rr := serve(router, http.MethodHead, "/x")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+			assert.True(t, rr.Header().Get("Allow") == "GET, POST")
+		}
+		{
+			rr := serve(router, http.MethodOptions, "/x")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+			assert.True(t, rr.Header().Get("Allow") == "GET, POST")
+		}
+	})
+
+	t.Run("head", func(t *testing.T) {
+		router := makeRouter(true, false)
+		{
+			rr := serve(router, http.MethodHead, "/x")
+			assert.True(t, rr.Code == http.StatusOK)
+			assert.True(t, rr.Header().Get("X-Test") == "get")
+			assert.True(t, rr.Body.Len() == 0)
+		}
+		{
+			rr := serve(router, http.MethodHead, "/y")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+			assert.True(t, rr.Header().Get("Allow") == "OPTIONS, POST")
+		}
+		{
+			rr := serve(router, http.MethodDelete, "/x")
+			assert.True(t, rr.Header().Get("Allow") == "GET, HEAD, POST")
+		}
+	})
+
+	t.Run("options", func(t *testing.T) {
+		router := makeRouter(true, true)
+		{
+			rr := serve(router, http.MethodOptions, "/x")
+			assert.True(t, rr.Code == http.StatusNoContent)
+			assert.True(t, rr.Header().Get("Allow") == "GET, HEAD, OPTIONS, POST")
+		}
+		{
+			rr := serve(router, http.MethodOptions, "/y")
+			assert.True(t, rr.Body.String() == "custom options")
+		}
+		{
+			rr := serve(router, http.MethodOptions, "/z")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+	})
+}
+
 func checkTestAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("auth") != "1" {
@@ -85,23 +248,28 @@ func checkTestAuthMiddleware(next http.Handler) http.Handler {
 }
 
 func sendTestRequest(method, url string, auth bool) string {
+	_, body := doTestRequest(method, url, auth)
+	return body
+}
+
+func doTestRequest(method, url string, auth bool) (*http.Response, string) {
 	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, ""
+	}
 	if auth {
 		req.Header.Add("auth", "1")
 	}
-	if err != nil {
-		return ""
-	}
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return ""
+		return nil, ""
 	}
 	body, _ := ioutil.ReadAll(res.Body)
 	res.Body.Close()
-	return string(body)
+	return res, string(body)
 }
 
-func makeTestRouter() (http.Handler, []*Endpoint) {
+func makeTestRouter(strict bool) (*Router, RouteErrors) {
 	getPathParamsFromContext := func(ctx context.Context, valueKey string) (value string, exists bool) {
 		if values, ok := ctx.Value(ParamsKey).(map[string]string); ok {
 			if values != nil {
@@ -116,6 +284,10 @@ func makeTestRouter() (http.Handler, []*Endpoint) {
 		NotFoundHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			_, _ = w.Write([]byte("not found"))
 		}),
+		MethodNotAllowedHandler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			_, _ = w.Write([]byte("method not allowed"))
+		}),
 		CommonPrefix: "v1",
 		PathSegmentValidator: func(s string) bool {
 			for _, r := range s {
@@ -126,6 +298,7 @@ func makeTestRouter() (http.Handler, []*Endpoint) {
 			return true
 		},
 		GlobalMiddlewares: nil,
+		Strict:            strict,
 	}
 
 	cfg.RootGroup.Subgroup(func(g *Group) {
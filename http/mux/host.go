@@ -0,0 +1,66 @@
+package mux
+
+import (
+	"net"
+	"sort"
+	"strings"
+)
+
+type methodTrees map[string]*trie
+
+func (hr *Router) forEachHost(host string, fn func(trees methodTrees) bool) {
+	// host matching is skipped only when every route is host agnostic
+	if _, ok := hr.hosts[""]; !ok || len(hr.hosts) > 1 {
+		host = normalizeHost(host)
+
+		if trees := hr.hosts[host]; trees != nil && host != "" {
+			if fn(trees) {
+				return
+			}
+		}
+
+		for _, pattern := range hr.wildcardHosts {
+			if matchWildcardHost(pattern, host) && fn(hr.hosts[pattern]) {
+				return
+			}
+		}
+	}
+
+	if trees := hr.hosts[""]; trees != nil {
+		fn(trees)
+	}
+}
+
+func (hr *Router) addWildcardHost(pattern string) {
+	hr.wildcardHosts = append(hr.wildcardHosts, pattern)
+
+	sort.SliceStable(hr.wildcardHosts, func(i, j int) bool {
+		return len(hr.wildcardHosts[i]) > len(hr.wildcardHosts[j])
+	})
+}
+
+func parseHostPattern(pattern string) (string, bool) {
+	pattern = strings.ToLower(pattern)
+
+	if strings.LastIndexByte(pattern, '*') > 0 || pattern == "*" || (isWildcardHost(pattern) && pattern[1] != '.') {
+		return "", false
+	}
+
+	return pattern, true
+}
+
+func isWildcardHost(pattern string) bool {
+	return pattern != "" && pattern[0] == '*'
+}
+
+func matchWildcardHost(pattern, host string) bool {
+	suffix := pattern[1:]
+	return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+}
+
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
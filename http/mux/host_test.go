@@ -0,0 +1,170 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Hosts(t *testing.T) {
+	makeHandler := func(s string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(s + " " + Path(r)))
+		})
+	}
+
+	g := new(Group)
+
+	g.Endpoint(http.MethodGet, "/", makeHandler("default"))
+	g.Endpoint(http.MethodGet, "/users/:id", makeHandler("default"))
+
+	g.Subgroup(func(g *Group) {
+		g.Host = "api.fantlab.ru"
+		g.Prefix = "/admin/v2"
+
+		g.Endpoint(http.MethodGet, "/users/:id", makeHandler("api")).WithName("admin_user")
+
+		g.Subgroup(func(g *Group) {
+			g.Prefix = "/stats"
+			g.Endpoint(http.MethodPost, "/", makeHandler("api stats"))
+		})
+	})
+
+	g.Subgroup(func(g *Group) {
+		g.Host = "*.fantlab.ru"
+
+		g.Endpoint(http.MethodGet, "/", makeHandler("wildcard"))
+	})
+
+	g.Subgroup(func(g *Group) {
+		g.Host = "*.m.fantlab.ru"
+
+		g.Endpoint(http.MethodGet, "/", makeHandler("mobile"))
+	})
+
+	g.Subgroup(func(g *Group) {
+		g.Host = "api.*.ru"
+
+		g.Endpoint(http.MethodGet, "/", makeHandler("bad"))
+	})
+
+	router, errs := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+		CommonPrefix:    "v1",
+	})
+
+	serve := func(method, host, path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(method, path, nil)
+		r.Host = host
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	t.Run("diagnostics", func(t *testing.T) {
+		assert.True(t, len(errs) == 1 && errs[0].Err == ErrInvalidHost)
+	})
+
+	t.Run("exact host", func(t *testing.T) {
+		{
+			rr := serve(http.MethodGet, "API.fantlab.ru:8080", "/v1/admin/v2/users/1")
+			assert.True(t, rr.Body.String() == "api /admin/v2/users/:id")
+		}
+		{
+			rr := serve(http.MethodPost, "api.fantlab.ru", "/v1/admin/v2/stats")
+			assert.True(t, rr.Body.String() == "api stats /admin/v2/stats")
+		}
+		{
+			rr := serve(http.MethodGet, "api.fantlab.ru", "/v1/users/1")
+			assert.True(t, rr.Body.String() == "default /users/:id")
+		}
+	})
+
+	t.Run("wildcard host", func(t *testing.T) {
+		{
+			rr := serve(http.MethodGet, "api.fantlab.ru", "/v1")
+			assert.True(t, rr.Body.String() == "wildcard /")
+		}
+		{
+			rr := serve(http.MethodGet, "x.m.fantlab.ru", "/v1")
+			assert.True(t, rr.Body.String() == "mobile /")
+		}
+		{
+			rr := serve(http.MethodGet, "fantlab.ru", "/v1")
+			assert.True(t, rr.Body.String() == "default /")
+		}
+	})
+
+	t.Run("other host", func(t *testing.T) {
+		{
+			rr := serve(http.MethodGet, "example.com", "/v1/admin/v2/users/1")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+		{
+			rr := serve(http.MethodPost, "example.com", "/v1/users/1")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+			assert.True(t, rr.Header().Get("Allow") == "GET")
+		}
+	})
+
+	t.Run("routes", func(t *testing.T) {
+		routes := router.Routes()
+		assert.True(t, routes[2].Host == "api.fantlab.ru" && routes[2].Path == "/v1/admin/v2/users/:id")
+
+		u, err := router.URL("admin_user", "id", "1")
+		assert.True(t, err == nil && u == "/v1/admin/v2/users/1")
+	})
+}
+
+func Test_SingleHost(t *testing.T) {
+	g := &Group{Host: "api.fantlab.ru"}
+
+	g.Endpoint(http.MethodGet, "/a", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("a"))
+	}))
+
+	router, errs := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	assert.True(t, len(errs) == 0)
+
+	serve := func(host string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/a", nil)
+		r.Host = host
+		router.ServeHTTP(rr, r)
+		return rr
+	}
+
+	t.Run("matching host", func(t *testing.T) {
+		rr := serve("api.fantlab.ru")
+		assert.True(t, rr.Code == http.StatusOK && rr.Body.String() == "a")
+	})
+
+	t.Run("other host", func(t *testing.T) {
+		assert.True(t, serve("fantlab.ru").Code == http.StatusNotFound)
+	})
+}
+
+func Test_HostPattern(t *testing.T) {
+	for pattern, valid := range map[string]bool{
+		"":               true,
+		"fantlab.ru":     true,
+		"*.fantlab.ru":   true,
+		"*":              false,
+		"*fantlab.ru":    false,
+		"api.*.ru":       false,
+		"*.*.fantlab.ru": false,
+	} {
+		_, ok := parseHostPattern(pattern)
+		assert.True(t, ok == valid)
+	}
+
+	assert.True(t, normalizeHost("FantLab.ru.") == "fantlab.ru")
+	assert.True(t, normalizeHost("[::1]:80") == "::1")
+}
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/FantLab/go-kit/http/mux"
+	"github.com/FantLab/go-kit/http/mux/middleware"
+)
+
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const unmatchedRoute = "unmatched"
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+type requestKey struct {
+	routeKey
+	code int
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type Collector struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	durations map[routeKey]*histogram
+	inFlight  map[routeKey]int64
+}
+
+func New(namespace string, buckets []float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &Collector{
+		namespace: namespace,
+		buckets:   sorted,
+		requests:  make(map[requestKey]uint64),
+		durations: make(map[routeKey]*histogram),
+		inFlight:  make(map[routeKey]int64),
+	}
+}
+
+func (c *Collector) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := routeKey{method: r.Method, route: mux.Path(r)}
+
+		if key.route == "" {
+			key.route = unmatchedRoute
+		}
+
+		c.mu.Lock()
+		c.inFlight[key]++
+		c.mu.Unlock()
+
+		t := time.Now()
+		sw := middleware.WrapResponseWriter(w)
+
+		defer func() {
+			c.observe(key, sw.Status(), time.Since(t))
+		}()
+
+		next.ServeHTTP(sw, r)
+	})
+}
+
+func (c *Collector) observe(key routeKey, code int, duration time.Duration) {
+	seconds := duration.Seconds()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight[key]--
+	c.requests[requestKey{routeKey: key, code: code}]++
+
+	h := c.durations[key]
+
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+
+		c.durations[key] = h
+	}
+
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.sum += seconds
+	h.count++
+}
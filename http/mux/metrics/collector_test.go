@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+func Test_Collector(t *testing.T) {
+	c := New("fantlab", []float64{10, 1})
+
+	var inFlight string
+
+	g := new(mux.Group)
+
+	g.Middleware(c.Middleware)
+
+	g.Endpoint(http.MethodGet, "/works/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		c.Handler().ServeHTTP(rec, r)
+		inFlight = rec.Body.String()
+	}))
+	g.Endpoint(http.MethodPost, "/works", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/works/1", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/works/2", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/works", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	t.Run("in_flight", func(t *testing.T) {
+		assert.True(t, strings.Contains(inFlight, `fantlab_http_requests_in_flight{method="GET",route="/works/:id"} 1`+"\n"))
+	})
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := rec.Body.String()
+
+	t.Run("content_type", func(t *testing.T) {
+		assert.True(t, rec.Header().Get("Content-Type") == ContentType)
+	})
+
+	t.Run("requests", func(t *testing.T) {
+		assert.True(t, strings.Contains(body, "# TYPE fantlab_http_requests_total counter\n"+
+			`fantlab_http_requests_total{method="POST",route="/works",code="201"} 1`+"\n"+
+			`fantlab_http_requests_total{method="GET",route="/works/:id",code="200"} 2`+"\n"))
+		assert.True(t, !strings.Contains(body, "/unknown"))
+	})
+
+	t.Run("durations", func(t *testing.T) {
+		assert.True(t, strings.Contains(body, "# TYPE fantlab_http_request_duration_seconds histogram\n"))
+		assert.True(t, strings.Contains(body, `fantlab_http_request_duration_seconds_bucket{method="GET",route="/works/:id",le="1"} 2`+"\n"+
+			`fantlab_http_request_duration_seconds_bucket{method="GET",route="/works/:id",le="10"} 2`+"\n"+
+			`fantlab_http_request_duration_seconds_bucket{method="GET",route="/works/:id",le="+Inf"} 2`+"\n"))
+		assert.True(t, strings.Contains(body, `fantlab_http_request_duration_seconds_count{method="GET",route="/works/:id"} 2`+"\n"))
+	})
+
+	t.Run("in_flight_done", func(t *testing.T) {
+		assert.True(t, strings.Contains(body, `fantlab_http_requests_in_flight{method="GET",route="/works/:id"} 0`+"\n"))
+	})
+}
+
+func Test_Unmatched(t *testing.T) {
+	c := New("", nil)
+
+	c.Middleware(http.NotFoundHandler()).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/x", nil))
+
+	rec := httptest.NewRecorder()
+	c.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.True(t, strings.Contains(rec.Body.String(), `http_requests_total{method="GET",route="unmatched",code="404"} 1`+"\n"))
+}
+
+func Test_EscapeLabel(t *testing.T) {
+	assert.True(t, escapeLabel("a\\b\"c\nd") == `a\\b\"c\nd`)
+}
@@ -0,0 +1,156 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+
+		c.writeMetrics(&buf)
+
+		w.Header().Set("Content-Type", ContentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+func (c *Collector) writeMetrics(buf *bytes.Buffer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writeRequests(buf)
+	c.writeDurations(buf)
+	c.writeInFlight(buf)
+}
+
+func (c *Collector) name(s string) string {
+	if c.namespace == "" {
+		return s
+	}
+	return c.namespace + "_" + s
+}
+
+func (c *Collector) writeRequests(buf *bytes.Buffer) {
+	name := c.name("http_requests_total")
+
+	writeHeader(buf, name, "counter", "Total number of HTTP requests.")
+
+	keys := make([]requestKey, 0, len(c.requests))
+	for key := range c.requests {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].routeKey != keys[j].routeKey {
+			return lessRouteKey(keys[i].routeKey, keys[j].routeKey)
+		}
+		return keys[i].code < keys[j].code
+	})
+
+	for _, key := range keys {
+		writeSample(buf, name, key.routeKey, "code", strconv.Itoa(key.code), float64(c.requests[key]))
+	}
+}
+
+func (c *Collector) writeDurations(buf *bytes.Buffer) {
+	name := c.name("http_request_duration_seconds")
+
+	writeHeader(buf, name, "histogram", "HTTP request latencies in seconds.")
+
+	for _, key := range sortedRouteKeys(c.durations) {
+		h := c.durations[key]
+
+		for i, bound := range c.buckets {
+			writeSample(buf, name+"_bucket", key, "le", formatFloat(bound), float64(h.counts[i]))
+		}
+
+		writeSample(buf, name+"_bucket", key, "le", "+Inf", float64(h.count))
+		writeSample(buf, name+"_sum", key, "", "", h.sum)
+		writeSample(buf, name+"_count", key, "", "", float64(h.count))
+	}
+}
+
+func (c *Collector) writeInFlight(buf *bytes.Buffer) {
+	name := c.name("http_requests_in_flight")
+
+	writeHeader(buf, name, "gauge", "Number of HTTP requests being served.")
+
+	keys := make([]routeKey, 0, len(c.inFlight))
+	for key := range c.inFlight {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return lessRouteKey(keys[i], keys[j])
+	})
+
+	for _, key := range keys {
+		writeSample(buf, name, key, "", "", float64(c.inFlight[key]))
+	}
+}
+
+func writeHeader(buf *bytes.Buffer, name, typ, help string) {
+	buf.WriteString("# HELP ")
+	buf.WriteString(name)
+	buf.WriteByte(' ')
+	buf.WriteString(help)
+	buf.WriteString("\n# TYPE ")
+	buf.WriteString(name)
+	buf.WriteByte(' ')
+	buf.WriteString(typ)
+	buf.WriteByte('\n')
+}
+
+func writeSample(buf *bytes.Buffer, name string, key routeKey, extraLabel, extraValue string, value float64) {
+	buf.WriteString(name)
+	buf.WriteString(`{method="`)
+	buf.WriteString(escapeLabel(key.method))
+	buf.WriteString(`",route="`)
+	buf.WriteString(escapeLabel(key.route))
+	buf.WriteByte('"')
+	if extraLabel != "" {
+		buf.WriteByte(',')
+		buf.WriteString(extraLabel)
+		buf.WriteString(`="`)
+		buf.WriteString(escapeLabel(extraValue))
+		buf.WriteByte('"')
+	}
+	buf.WriteString("} ")
+	buf.WriteString(formatFloat(value))
+	buf.WriteByte('\n')
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func lessRouteKey(x, y routeKey) bool {
+	if x.route != y.route {
+		return x.route < y.route
+	}
+	return x.method < y.method
+}
+
+func sortedRouteKeys(m map[routeKey]*histogram) []routeKey {
+	keys := make([]routeKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lessRouteKey(keys[i], keys[j])
+	})
+	return keys
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+type AccessLogEntry struct {
+	Method     string
+	Host       string
+	Path       string
+	Route      string
+	Status     int
+	Bytes      int64
+	RequestID  string
+	RemoteAddr string
+	UserAgent  string
+	Time       time.Time
+	Duration   time.Duration
+}
+
+type AccessLogFunc func(context.Context, AccessLogEntry)
+
+func AccessLog(f AccessLogFunc) mux.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t := time.Now()
+			sw := WrapResponseWriter(w)
+			bytes := sw.bytes
+
+			defer func() {
+				f(r.Context(), AccessLogEntry{
+					Method:     r.Method,
+					Host:       r.Host,
+					Path:       r.URL.Path,
+					Route:      mux.Path(r),
+					Status:     sw.Status(),
+					Bytes:      sw.bytes - bytes,
+					RequestID:  GetRequestID(r.Context()),
+					RemoteAddr: r.RemoteAddr,
+					UserAgent:  r.UserAgent(),
+					Time:       t,
+					Duration:   time.Since(t),
+				})
+			}()
+
+			next.ServeHTTP(sw, r)
+		})
+	}
+}
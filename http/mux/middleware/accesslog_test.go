@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+func Test_AccessLog(t *testing.T) {
+	var entries []AccessLogEntry
+
+	g := new(mux.Group)
+
+	g.Middleware(AccessLog(func(ctx context.Context, entry AccessLogEntry) {
+		entries = append(entries, entry)
+	}))
+
+	g.Endpoint(http.MethodPost, "/works/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:         g,
+		NotFoundHandler:   http.NotFoundHandler(),
+		GlobalMiddlewares: []mux.Middleware{RequestID("", func() string { return "req-1" })},
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/works/42", nil)
+	r.Header.Set("User-Agent", "test")
+
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.True(t, len(entries) == 1)
+
+	entry := entries[0]
+
+	assert.True(t, entry.Method == http.MethodPost)
+	assert.True(t, entry.Path == "/works/42")
+	assert.True(t, entry.Route == "/works/:id")
+	assert.True(t, entry.Status == http.StatusCreated)
+	assert.True(t, entry.Bytes == 7)
+	assert.True(t, entry.RequestID == "req-1")
+	assert.True(t, entry.UserAgent == "test")
+	assert.True(t, !entry.Time.IsZero() && entry.Duration >= 0)
+}
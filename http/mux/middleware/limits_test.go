@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Timeout(t *testing.T) {
+	handler := Timeout(10*time.Millisecond, "timeout")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+			_, _ = w.Write([]byte("late"))
+		}
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, rr.Code == http.StatusServiceUnavailable)
+	assert.True(t, rr.Body.String() == "timeout")
+}
+
+func Test_MaxBodySize(t *testing.T) {
+	var readErr error
+
+	handler := MaxBodySize(4)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = ioutil.ReadAll(r.Body)
+	}))
+
+	t.Run("content length", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345")))
+
+		assert.True(t, rr.Code == http.StatusRequestEntityTooLarge)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("12345"))
+		r.ContentLength = -1
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.True(t, readErr != nil)
+	})
+
+	t.Run("ok", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("1234")))
+
+		assert.True(t, rr.Code == http.StatusOK && readErr == nil)
+	})
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Recover(t *testing.T) {
+	var reported interface{}
+
+	mw := Recover(func(r *http.Request, err interface{}) {
+		reported = err
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.True(t, rr.Code == http.StatusInternalServerError)
+		assert.True(t, reported == "boom")
+	})
+
+	t.Run("panic after write", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			panic("late")
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.True(t, rr.Code == http.StatusAccepted)
+		assert.True(t, reported == "late")
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		defer func() {
+			assert.True(t, recover() == http.ErrAbortHandler)
+		}()
+
+		Recover(nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
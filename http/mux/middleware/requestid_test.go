@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_RequestID(t *testing.T) {
+	var id string
+
+	handler := RequestID("", nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id = GetRequestID(r.Context())
+	}))
+
+	t.Run("generate", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.True(t, len(id) == 32)
+		assert.True(t, rr.Header().Get(RequestIDHeader) == id)
+	})
+
+	t.Run("propagate", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(RequestIDHeader, "upstream-1")
+		handler.ServeHTTP(rr, r)
+
+		assert.True(t, id == "upstream-1")
+		assert.True(t, rr.Header().Get(RequestIDHeader) == "upstream-1")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, value := range []string{"a b", strings.Repeat("x", maxRequestIDLength+1), "ж"} {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set(RequestIDHeader, value)
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.True(t, id != value && len(id) == 32)
+		}
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		RequestID("X-Trace", func() string { return "fixed" })(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id = GetRequestID(r.Context())
+		})).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.True(t, id == "fixed" && rr.Header().Get("X-Trace") == "fixed")
+	})
+}
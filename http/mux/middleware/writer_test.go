@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_StatusWriter(t *testing.T) {
+	t.Run("implicit status", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		sw := WrapResponseWriter(rr)
+
+		assert.True(t, !sw.WroteHeader() && sw.Status() == http.StatusOK)
+
+		_, _ = sw.Write([]byte("abc"))
+		sw.WriteHeader(http.StatusTeapot)
+
+		assert.True(t, sw.WroteHeader() && sw.Status() == http.StatusOK && sw.BytesWritten() == 3)
+	})
+
+	t.Run("explicit status", func(t *testing.T) {
+		sw := WrapResponseWriter(httptest.NewRecorder())
+		sw.WriteHeader(http.StatusNotFound)
+		sw.Flush()
+
+		assert.True(t, sw.Status() == http.StatusNotFound)
+	})
+
+	t.Run("no double wrap", func(t *testing.T) {
+		sw := WrapResponseWriter(httptest.NewRecorder())
+
+		assert.True(t, WrapResponseWriter(sw) == sw)
+		assert.True(t, sw.Unwrap() != nil)
+
+		_, _, err := sw.Hijack()
+		assert.True(t, err != nil)
+	})
+}
@@ -3,15 +3,46 @@ package mux
 import "net/http"
 
 type Endpoint struct {
-	Method  string
-	Path    string
-	Handler http.Handler
+	Method      string
+	Path        string
+	Handler     http.Handler
+	Middlewares []Middleware
+	Name        string
+	Summary     string
+	Tags        []string
 }
 
 type Middleware func(http.Handler) http.Handler
 
 type Group struct {
+	Prefix      string
+	Host        string
+	CORS        *CORS
 	Middlewares []Middleware
 	Endpoints   []*Endpoint
 	Subgroups   []*Group
 }
+
+func (e *Endpoint) String() string {
+	return e.Method + " " + e.Path
+}
+
+func (e *Endpoint) WithName(name string) *Endpoint {
+	e.Name = name
+	return e
+}
+
+func (e *Endpoint) WithSummary(summary string) *Endpoint {
+	e.Summary = summary
+	return e
+}
+
+func (e *Endpoint) WithTags(tags ...string) *Endpoint {
+	e.Tags = append(e.Tags, tags...)
+	return e
+}
+
+func (e *Endpoint) WithMiddlewares(mws ...Middleware) *Endpoint {
+	e.Middlewares = append(e.Middlewares, mws...)
+	return e
+}
@@ -0,0 +1,164 @@
+package openapi
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+const Version = "3.0.3"
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string              `json:"operationId,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []*Parameter        `json:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Type    string `json:"type"`
+	Format  string `json:"format,omitempty"`
+	Pattern string `json:"pattern,omitempty"`
+	Minimum *int   `json:"minimum,omitempty"`
+}
+
+type Response struct {
+	Description string `json:"description"`
+}
+
+// a document describes a single host pattern as it is set on the group, routes without a host
+// are served on every host and are included unless a route of the host has the same path and method
+func Generate(info Info, host string, routes []mux.Route) *Document {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+	}
+
+	hosts := []string{""}
+
+	if host != "" {
+		hosts = append(hosts, strings.ToLower(host))
+	}
+
+	for _, host := range hosts {
+		doc.addRoutes(host, routes)
+	}
+
+	return doc
+}
+
+func (doc *Document) addRoutes(host string, routes []mux.Route) {
+	for _, route := range routes {
+		if route.Host != host {
+			continue
+		}
+
+		path := convertPath(route.Path)
+
+		item := doc.Paths[path]
+
+		if item == nil {
+			item = make(PathItem)
+
+			doc.Paths[path] = item
+		}
+
+		item[strings.ToLower(route.Method)] = &Operation{
+			OperationID: route.Name,
+			Summary:     route.Summary,
+			Tags:        route.Tags,
+			Parameters:  convertParams(route.Params),
+			Responses: map[string]Response{
+				"default": {Description: "Default response"},
+			},
+		}
+	}
+}
+
+func (doc *Document) MarshalIndent() ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+func convertPath(path string) string {
+	segments := strings.Split(path, "/")
+
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+
+		switch segment[0] {
+		case ':':
+			name := segment[1:]
+			if j := strings.IndexByte(name, '<'); j >= 0 {
+				name = name[:j]
+			}
+			segments[i] = "{" + name + "}"
+		case '*':
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func convertParams(params []mux.RouteParam) []*Parameter {
+	if len(params) == 0 {
+		return nil
+	}
+
+	result := make([]*Parameter, len(params))
+
+	for i, param := range params {
+		p := &Parameter{
+			Name:     param.Name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		}
+
+		switch param.Constraint {
+		case "":
+		case "int":
+			p.Schema = &Schema{Type: "integer", Format: "int64"}
+		case "uint":
+			min := 0
+			p.Schema = &Schema{Type: "integer", Format: "int64", Minimum: &min}
+		default:
+			p.Schema.Pattern = "^(?:" + param.Constraint + ")$"
+		}
+
+		if param.CatchAll {
+			p.Description = "Remainder of the path"
+		}
+
+		result[i] = p
+	}
+
+	return result
+}
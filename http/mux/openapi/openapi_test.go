@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+func Test_Generate(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	g := new(mux.Group)
+
+	g.Endpoint(http.MethodGet, "/works/:id<int>", emptyHandler).WithName("getWork").WithSummary("Work").WithTags("works")
+	g.Endpoint(http.MethodDelete, "/works/:id<int>", emptyHandler)
+	g.Endpoint(http.MethodGet, "/users/:login<[a-z]+>/files/*path", emptyHandler)
+	g.Endpoint(http.MethodGet, "/pages/:page<uint>", emptyHandler)
+	g.Endpoint(http.MethodGet, "/authors/:id/", emptyHandler)
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+		CommonPrefix:    "v1",
+	})
+
+	doc := Generate(Info{Title: "API", Version: "1.0"}, "", router.Routes())
+
+	t.Run("paths", func(t *testing.T) {
+		assert.True(t, len(doc.Paths) == 4)
+
+		work := doc.Paths["/v1/works/{id}"]
+		assert.True(t, len(work) == 2 && work["get"] != nil && work["delete"] != nil)
+		assert.True(t, work["get"].OperationID == "getWork" && work["get"].Summary == "Work")
+		assert.DeepEqual(t, work["get"].Tags, []string{"works"})
+		assert.DeepEqual(t, work["get"].Parameters, []*Parameter{{
+			Name:     "id",
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "integer", Format: "int64"},
+		}})
+
+		files := doc.Paths["/v1/users/{login}/files/{path}"]["get"]
+		assert.True(t, files != nil && len(files.Parameters) == 2)
+		assert.True(t, files.Parameters[0].Schema.Pattern == "^(?:[a-z]+)$")
+		assert.True(t, files.Parameters[1].Description != "")
+
+		page := doc.Paths["/v1/pages/{page}"]["get"]
+		assert.True(t, page != nil && *page.Parameters[0].Schema.Minimum == 0)
+
+		assert.True(t, doc.Paths["/v1/authors/{id}/"]["get"] != nil)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := doc.MarshalIndent()
+		assert.True(t, err == nil)
+
+		var raw map[string]interface{}
+		assert.True(t, json.Unmarshal(data, &raw) == nil)
+		assert.True(t, raw["openapi"] == Version)
+		assert.DeepEqual(t, raw["info"], map[string]interface{}{"title": "API", "version": "1.0"})
+	})
+}
+
+func Test_GenerateHost(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	g := new(mux.Group)
+
+	g.Endpoint(http.MethodGet, "/works/:id", emptyHandler).WithSummary("any")
+	g.Endpoint(http.MethodGet, "/about", emptyHandler).WithSummary("any")
+
+	g.Subgroup(func(g *mux.Group) {
+		g.Host = "api.fantlab.ru"
+
+		g.Endpoint(http.MethodGet, "/works/:id", emptyHandler).WithSummary("api")
+		g.Endpoint(http.MethodGet, "/keys", emptyHandler).WithSummary("api")
+	})
+
+	g.Subgroup(func(g *mux.Group) {
+		g.Host = "m.fantlab.ru"
+
+		g.Endpoint(http.MethodGet, "/works/:id", emptyHandler).WithSummary("m")
+	})
+
+	router, errs := mux.NewRouter(&mux.Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	assert.True(t, errs == nil)
+
+	info := Info{Title: "API", Version: "1.0"}
+
+	t.Run("any host", func(t *testing.T) {
+		doc := Generate(info, "", router.Routes())
+		assert.True(t, len(doc.Paths) == 2 && doc.Paths["/works/{id}"]["get"].Summary == "any")
+	})
+
+	t.Run("host", func(t *testing.T) {
+		doc := Generate(info, "API.fantlab.ru", router.Routes())
+		assert.True(t, len(doc.Paths) == 3 && doc.Paths["/works/{id}"]["get"].Summary == "api")
+		assert.True(t, doc.Paths["/about"]["get"] != nil && doc.Paths["/keys"]["get"] != nil)
+
+		doc = Generate(info, "m.fantlab.ru", router.Routes())
+		assert.True(t, len(doc.Paths) == 2 && doc.Paths["/works/{id}"]["get"].Summary == "m")
+	})
+}
@@ -0,0 +1,51 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+var ErrParamNotFound = errors.New("mux: path parameter not found")
+
+func Path(r *http.Request) string {
+	if rc := routeContextFrom(r.Context()); rc != nil {
+		return rc.path
+	}
+	return ""
+}
+
+func Params(r *http.Request) map[string]string {
+	if rc := routeContextFrom(r.Context()); rc != nil && len(rc.params) > 0 {
+		return rc.paramsMap()
+	}
+	return nil
+}
+
+func Param(r *http.Request, key string) string {
+	value, _ := LookupParam(r, key)
+	return value
+}
+
+func LookupParam(r *http.Request, key string) (string, bool) {
+	if rc := routeContextFrom(r.Context()); rc != nil {
+		return rc.lookup(key)
+	}
+	return "", false
+}
+
+func ParamInt64(r *http.Request, key string) (int64, error) {
+	value, ok := LookupParam(r, key)
+	if !ok {
+		return 0, ErrParamNotFound
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+func ParamUint64(r *http.Request, key string) (uint64, error) {
+	value, ok := LookupParam(r, key)
+	if !ok {
+		return 0, ErrParamNotFound
+	}
+	return strconv.ParseUint(value, 10, 64)
+}
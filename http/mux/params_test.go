@@ -0,0 +1,127 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Params(t *testing.T) {
+	var (
+		path     string
+		params   map[string]string
+		id       int64
+		idErr    error
+		missing  error
+		slug     string
+		hasSlug  bool
+		unsigned uint64
+	)
+
+	g := new(Group)
+
+	g.Endpoint(http.MethodGet, "/works/:id<int>/:slug<[a-z-]+>", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = Path(r)
+		params = Params(r)
+		id, idErr = ParamInt64(r, "id")
+		unsigned, _ = ParamUint64(r, "id")
+		slug, hasSlug = LookupParam(r, "slug")
+		_, missing = ParamInt64(r, "missing")
+		_, _ = w.Write([]byte("work"))
+	}))
+	g.Endpoint(http.MethodGet, "/works/:id/:slug", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("fallback " + Param(r, "id")))
+	}))
+	g.Endpoint(http.MethodGet, "/users/:id<uint>", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("user"))
+	}))
+
+	router, errs := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	assert.True(t, errs == nil)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	t.Run("typed", func(t *testing.T) {
+		rr := serve("/works/42/red-planet")
+		assert.True(t, rr.Body.String() == "work")
+		assert.True(t, path == "/works/:id<int>/:slug<[a-z-]+>")
+		assert.DeepEqual(t, params, map[string]string{"id": "42", "slug": "red-planet"})
+		assert.True(t, id == 42 && idErr == nil && unsigned == 42)
+		assert.True(t, slug == "red-planet" && hasSlug)
+		assert.True(t, missing == ErrParamNotFound)
+	})
+
+	t.Run("constraint fallthrough", func(t *testing.T) {
+		{
+			rr := serve("/works/abc/red-planet")
+			assert.True(t, rr.Body.String() == "fallback abc")
+		}
+		{
+			rr := serve("/works/42/Red_Planet")
+			assert.True(t, rr.Body.String() == "fallback 42")
+		}
+	})
+
+	t.Run("constraint not found", func(t *testing.T) {
+		{
+			rr := serve("/users/-1")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+		{
+			rr := serve("/users/1")
+			assert.True(t, rr.Body.String() == "user")
+		}
+	})
+
+	t.Run("no route", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		assert.True(t, Path(r) == "" && Params(r) == nil && Param(r, "id") == "")
+		_, err := ParamInt64(r, "id")
+		assert.True(t, err == ErrParamNotFound)
+	})
+}
+
+func Test_ParamConstraints(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("invalid", func(t *testing.T) {
+		trie := newPathTrie("", nil)
+
+		assert.True(t, trie.insertPathHandler("x/:id<int", emptyHandler, nil) != nil)
+		assert.True(t, trie.insertPathHandler("x/:id<[a-z>", emptyHandler, nil) != nil)
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		trie := newPathTrie("", nil)
+
+		assert.True(t, trie.insertPathHandler("x/:any", emptyHandler, nil) == nil)
+		assert.True(t, trie.insertPathHandler("x/:id<int>", emptyHandler, nil) == nil)
+		assert.True(t, trie.insertPathHandler("x/:code<[a-z]{3}>", emptyHandler, nil) == nil)
+		assert.True(t, trie.insertPathHandler("x/:num<int>", emptyHandler, nil).Filter(ErrAmbiguousParam) != nil)
+
+		assert.True(t, len(trie.root.children["x"].paramChildren) == 3)
+
+		{
+			_, params := lookupTestPath(trie, "x/10")
+			assert.DeepEqual(t, params, map[string]string{"id": "10", "num": "10"})
+		}
+		{
+			_, params := lookupTestPath(trie, "x/abc")
+			assert.DeepEqual(t, params, map[string]string{"code": "abc"})
+		}
+		{
+			_, params := lookupTestPath(trie, "x/abcd")
+			assert.DeepEqual(t, params, map[string]string{"any": "abcd"})
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package mux
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+type PathPolicy int
+
+const (
+	PathLenient PathPolicy = iota
+	PathStrict
+	PathRedirect
+)
+
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	if p[0] != '/' {
+		p = "/" + p
+	}
+	cleaned := path.Clean(p)
+	if p[len(p)-1] == '/' && cleaned != "/" {
+		cleaned += "/"
+	}
+	return cleaned
+}
+
+func (value *pathHandler) canonicalPath(cleaned string) string {
+	if value.catchAll {
+		return cleaned
+	}
+	trimmed := strings.TrimSuffix(cleaned, "/")
+	if trimmed == "" {
+		return "/"
+	}
+	if value.trailingSlash {
+		return trimmed + "/"
+	}
+	return trimmed
+}
+
+func (hr *Router) lookupFold(host, method, path string) (canonical string) {
+	methods := []string{method}
+
+	if method == http.MethodHead && hr.autoHead {
+		methods = append(methods, http.MethodGet)
+	}
+
+	hr.forEachHost(host, func(trees methodTrees) bool {
+		for _, method := range methods {
+			trie := trees[method]
+			if trie == nil {
+				continue
+			}
+			if value, corrected := trie.handlerForPathFold(path); value != nil {
+				if strings.HasSuffix(path, "/") && corrected != "/" {
+					corrected += "/"
+				}
+				canonical = value.canonicalPath(corrected)
+				return true
+			}
+		}
+		return false
+	})
+
+	return
+}
+
+// strict routers only fix letter case, not slashes or dot segments
+func (hr *Router) foldRedirect(canonical, requestPath string) bool {
+	if canonical == "" || canonical == requestPath {
+		return false
+	}
+	return hr.pathPolicy != PathStrict || strings.EqualFold(canonical, requestPath)
+}
+
+func redirect(w http.ResponseWriter, r *http.Request, path string) {
+	code := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		code = http.StatusMovedPermanently
+	}
+	u := url.URL{Path: path, RawQuery: r.URL.RawQuery}
+	http.Redirect(w, r, u.String(), code)
+}
@@ -0,0 +1,182 @@
+package mux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_PathPolicy(t *testing.T) {
+	makeRouter := func(policy PathPolicy, caseInsensitive bool) *Router {
+		g := new(Group)
+
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(Path(r)))
+		})
+
+		g.Endpoint(http.MethodGet, "/", handler)
+		g.Endpoint(http.MethodGet, "/forums", handler)
+		g.Endpoint(http.MethodPost, "/forums", handler)
+		g.Endpoint(http.MethodGet, "/forums/:id", handler)
+		g.Endpoint(http.MethodGet, "/Authors/:name/", handler)
+		g.Endpoint(http.MethodGet, "/static/*filepath", handler)
+
+		router, _ := NewRouter(&Config{
+			RootGroup:               g,
+			NotFoundHandler:         http.NotFoundHandler(),
+			CommonPrefix:            "v1",
+			PathPolicy:              policy,
+			RedirectCaseInsensitive: caseInsensitive,
+		})
+
+		return router
+	}
+
+	serve := func(router *Router, method, target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest(method, target, nil))
+		return rr
+	}
+
+	t.Run("clean path", func(t *testing.T) {
+		for p, cleaned := range map[string]string{
+			"":          "/",
+			"/":         "/",
+			"a/b":       "/a/b",
+			"/a//b/":    "/a/b/",
+			"/a/../b":   "/b",
+			"/a/./b/.":  "/a/b",
+			"/../../a/": "/a/",
+		} {
+			assert.True(t, cleanPath(p) == cleaned)
+		}
+	})
+
+	t.Run("lenient", func(t *testing.T) {
+		router := makeRouter(PathLenient, false)
+		{
+			rr := serve(router, http.MethodGet, "/v1//forums/")
+			assert.True(t, rr.Code == http.StatusOK && rr.Body.String() == "/forums")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/FORUMS")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		router := makeRouter(PathStrict, false)
+		{
+			rr := serve(router, http.MethodGet, "/v1/forums")
+			assert.True(t, rr.Code == http.StatusOK)
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/Authors/x/")
+			assert.True(t, rr.Code == http.StatusOK && rr.Body.String() == "/Authors/:name/")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1")
+			assert.True(t, rr.Code == http.StatusOK)
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/static/css/")
+			assert.True(t, rr.Code == http.StatusOK)
+		}
+		for _, target := range []string{"/v1/forums/", "/v1//forums", "/v1/x/../forums", "/v1/Authors/x", "/v1/"} {
+			rr := serve(router, http.MethodGet, target)
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+		{
+			rr := serve(router, http.MethodDelete, "/v1/forums/")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+		{
+			rr := serve(router, http.MethodDelete, "/v1/forums")
+			assert.True(t, rr.Code == http.StatusMethodNotAllowed)
+		}
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		router := makeRouter(PathRedirect, false)
+		{
+			rr := serve(router, http.MethodGet, "/v1/forums")
+			assert.True(t, rr.Code == http.StatusOK)
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1//forums/?page=2")
+			assert.True(t, rr.Code == http.StatusMovedPermanently)
+			assert.True(t, rr.Header().Get("Location") == "/v1/forums?page=2")
+		}
+		{
+			rr := serve(router, http.MethodPost, "/v1/forums/")
+			assert.True(t, rr.Code == http.StatusPermanentRedirect)
+			assert.True(t, rr.Header().Get("Location") == "/v1/forums")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/forums/x/../1")
+			assert.True(t, rr.Code == http.StatusMovedPermanently)
+			assert.True(t, rr.Header().Get("Location") == "/v1/forums/1")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/Authors/x")
+			assert.True(t, rr.Code == http.StatusMovedPermanently)
+			assert.True(t, rr.Header().Get("Location") == "/v1/Authors/x/")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/forums/a%5Cb/")
+			assert.True(t, rr.Header().Get("Location") == "/v1/forums/a%5Cb")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/static//css/")
+			assert.True(t, rr.Header().Get("Location") == "/v1/static/css/")
+		}
+	})
+
+	t.Run("case insensitive", func(t *testing.T) {
+		router := makeRouter(PathRedirect, true)
+		{
+			rr := serve(router, http.MethodGet, "/V1/FORUMS/Abc/")
+			assert.True(t, rr.Code == http.StatusMovedPermanently)
+			assert.True(t, rr.Header().Get("Location") == "/v1/forums/Abc")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/authors/Bob")
+			assert.True(t, rr.Header().Get("Location") == "/v1/Authors/Bob/")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/STATIC/Css/")
+			assert.True(t, rr.Header().Get("Location") == "/v1/static/Css/")
+		}
+		{
+			rr := serve(router, http.MethodDelete, "/v1/FORUMS")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/users")
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+	})
+
+	t.Run("strict case insensitive", func(t *testing.T) {
+		router := makeRouter(PathStrict, true)
+		{
+			rr := serve(router, http.MethodGet, "/V1/FORUMS/Abc?page=2")
+			assert.True(t, rr.Code == http.StatusMovedPermanently)
+			assert.True(t, rr.Header().Get("Location") == "/v1/forums/Abc?page=2")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/authors/Bob/")
+			assert.True(t, rr.Header().Get("Location") == "/v1/Authors/Bob/")
+		}
+		{
+			rr := serve(router, http.MethodGet, "/v1/forums/1")
+			assert.True(t, rr.Code == http.StatusOK)
+		}
+		for _, target := range []string{"/v1/forums/1/", "/v1//forums/1", "/v1/x/../forums/2", "/v1/FORUMS/1/", "/v1/authors/Bob"} {
+			rr := serve(router, http.MethodGet, target)
+			assert.True(t, rr.Code == http.StatusNotFound)
+		}
+	})
+}
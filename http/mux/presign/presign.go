@@ -0,0 +1,140 @@
+package presign
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+var (
+	ErrMissingSignature = errors.New("presign: missing signature")
+	ErrInvalidSignature = errors.New("presign: invalid signature")
+	ErrExpired          = errors.New("presign: link expired")
+)
+
+const (
+	ExpiresParam   = "expires"
+	SignatureParam = "signature"
+
+	signatureDomain = "presign/url/v1"
+)
+
+// implemented by *signed.Coder
+type Signer interface {
+	SignMessage(domain string, message []byte) []byte
+}
+
+// implemented by *signed.Coder and *signed.Verifier
+type Verifier interface {
+	VerifyMessage(domain string, message, signature []byte) error
+}
+
+// params lists the query parameters covered by the signature, the others may be changed freely
+func SignURL(s Signer, method string, u *url.URL, params []string, expires time.Time) *url.URL {
+	expiresUnix := strconv.FormatInt(expires.Unix(), 10)
+
+	signature := string(s.SignMessage(signatureDomain, canonical(method, u, params, expiresUnix)))
+
+	query := u.Query()
+	query.Set(ExpiresParam, expiresUnix)
+	query.Set(SignatureParam, signature)
+
+	signedURL := *u
+	signedURL.RawQuery = query.Encode()
+
+	return &signedURL
+}
+
+// HEAD requests are verified as GET
+func VerifyRequest(v Verifier, r *http.Request, params []string, now time.Time) error {
+	query := r.URL.Query()
+
+	signature, expiresUnix := query.Get(SignatureParam), query.Get(ExpiresParam)
+
+	if signature == "" || expiresUnix == "" {
+		return ErrMissingSignature
+	}
+
+	expires, err := strconv.ParseInt(expiresUnix, 10, 64)
+
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	method := r.Method
+
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+
+	payload := canonical(method, r.URL, params, expiresUnix)
+
+	if v.VerifyMessage(signatureDomain, payload, []byte(signature)) != nil {
+		return ErrInvalidSignature
+	}
+
+	if !now.Before(time.Unix(expires, 0)) {
+		return ErrExpired
+	}
+
+	return nil
+}
+
+type Config struct {
+	Verifier     Verifier
+	Params       []string
+	Clock        func() time.Time
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func Middleware(cfg *Config) mux.Middleware {
+	clock := cfg.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	errorHandler := cfg.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := VerifyRequest(cfg.Verifier, r, cfg.Params, clock()); err != nil {
+				errorHandler(w, r, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func canonical(method string, u *url.URL, params []string, expiresUnix string) []byte {
+	query := u.Query()
+	selected := make(url.Values, len(params))
+
+	for _, param := range params {
+		if values, ok := query[param]; ok {
+			selected[param] = values
+		}
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString(method)
+	sb.WriteByte('\n')
+	sb.WriteString(u.EscapedPath())
+	sb.WriteByte('\n')
+	sb.WriteString(selected.Encode())
+	sb.WriteByte('\n')
+	sb.WriteString(expiresUnix)
+
+	return []byte(sb.String())
+}
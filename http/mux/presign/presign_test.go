@@ -0,0 +1,117 @@
+package presign
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+func Test_Presign(t *testing.T) {
+	coder, _ := signed.Generate()
+
+	now := time.Unix(1000000, 0)
+	params := []string{"format"}
+
+	var lastErr error
+
+	g := new(mux.Group)
+
+	g.Middleware(Middleware(&Config{
+		Verifier: coder.Verifier(),
+		Params:   params,
+		Clock:    func() time.Time { return now },
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			lastErr = err
+			w.WriteHeader(http.StatusForbidden)
+		},
+	}))
+
+	g.Endpoint(http.MethodGet, "/exports/:id", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("export " + mux.Param(r, "id")))
+	}))
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+		AutoHead:        true,
+	})
+
+	u, _ := url.Parse("/exports/42?format=bibtex")
+	link := SignURL(coder, http.MethodGet, u, params, now.Add(time.Hour)).String()
+
+	serve := func(method, target string) int {
+		lastErr = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w.Code
+	}
+
+	t.Run("positive", func(t *testing.T) {
+		assert.True(t, serve(http.MethodGet, link) == http.StatusOK)
+		assert.True(t, serve(http.MethodHead, link) == http.StatusOK)
+		assert.True(t, serve(http.MethodGet, link+"&utm=1") == http.StatusOK)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		assert.True(t, serve(http.MethodGet, "/exports/42?format=bibtex") == http.StatusForbidden)
+		assert.True(t, lastErr == ErrMissingSignature)
+	})
+
+	t.Run("tampered_path", func(t *testing.T) {
+		tampered, _ := url.Parse(link)
+		tampered.Path = "/exports/43"
+		assert.True(t, serve(http.MethodGet, tampered.String()) == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidSignature)
+	})
+
+	t.Run("tampered_params", func(t *testing.T) {
+		tampered, _ := url.Parse(link)
+		query := tampered.Query()
+		query.Set("format", "csv")
+		tampered.RawQuery = query.Encode()
+		assert.True(t, serve(http.MethodGet, tampered.String()) == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidSignature)
+
+		query.Set("format", "bibtex")
+		query.Set(ExpiresParam, "9999999999")
+		tampered.RawQuery = query.Encode()
+		assert.True(t, serve(http.MethodGet, tampered.String()) == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidSignature)
+	})
+
+	t.Run("method", func(t *testing.T) {
+		postLink := SignURL(coder, http.MethodPost, u, params, now.Add(time.Hour)).String()
+		assert.True(t, serve(http.MethodGet, postLink) == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidSignature)
+	})
+
+	t.Run("not_a_token", func(t *testing.T) {
+		signedURL, _ := url.Parse(link)
+		query := signedURL.Query()
+		payload := canonical(http.MethodGet, signedURL, params, query.Get(ExpiresParam))
+		token := base64.RawURLEncoding.EncodeToString(payload) + "." + query.Get(SignatureParam)
+
+		_, err := coder.Decode([]byte(token))
+		assert.True(t, err == signed.ErrSign)
+		assert.True(t, coder.DecodeClaims([]byte(token), new(signed.Claims), nil) == signed.ErrSign)
+
+		encoded := string(coder.Encode(payload))
+		query.Set(SignatureParam, encoded[len(encoded)-len(query.Get(SignatureParam)):])
+		signedURL.RawQuery = query.Encode()
+		assert.True(t, serve(http.MethodGet, signedURL.String()) == http.StatusForbidden)
+		assert.True(t, lastErr == ErrInvalidSignature)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		assert.True(t, serve(http.MethodGet, link) == http.StatusForbidden)
+		assert.True(t, lastErr == ErrExpired)
+	})
+}
@@ -0,0 +1,122 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// State is the opaque per-key state an Algorithm keeps in a Store
+type State struct {
+	Value float64
+	Prev  float64
+	Time  time.Time
+}
+
+type Algorithm interface {
+	Limit() int
+	TTL() time.Duration
+	Take(state *State, now time.Time) Result
+}
+
+// bucket of burst tokens refilled completely over interval
+type TokenBucket struct {
+	Burst    int
+	Interval time.Duration
+}
+
+func (tb TokenBucket) Limit() int {
+	return tb.Burst
+}
+
+func (tb TokenBucket) TTL() time.Duration {
+	return tb.Interval
+}
+
+func (tb TokenBucket) Take(state *State, now time.Time) Result {
+	burst := float64(tb.Burst)
+	rate := burst / tb.Interval.Seconds()
+
+	if state.Time.IsZero() {
+		state.Value = burst
+	} else if elapsed := now.Sub(state.Time); elapsed > 0 {
+		state.Value = math.Min(burst, state.Value+elapsed.Seconds()*rate)
+	}
+
+	state.Time = now
+
+	result := Result{Limit: tb.Burst}
+
+	if state.Value >= 1 {
+		state.Value--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - state.Value) / rate)
+	}
+
+	result.Remaining = int(state.Value)
+	result.Reset = seconds((burst - state.Value) / rate)
+
+	return result
+}
+
+// approximated sliding window weighting the previous fixed window count
+type SlidingWindow struct {
+	Requests int
+	Window   time.Duration
+}
+
+func (sw SlidingWindow) Limit() int {
+	return sw.Requests
+}
+
+func (sw SlidingWindow) TTL() time.Duration {
+	return 2 * sw.Window
+}
+
+func (sw SlidingWindow) Take(state *State, now time.Time) Result {
+	start := now.Truncate(sw.Window)
+
+	if !state.Time.Equal(start) {
+		if start.Sub(state.Time) == sw.Window {
+			state.Prev = state.Value
+		} else {
+			state.Prev = 0
+		}
+		state.Value = 0
+		state.Time = start
+	}
+
+	limit := float64(sw.Requests)
+	elapsed := now.Sub(start)
+	count := state.Prev*(1-elapsed.Seconds()/sw.Window.Seconds()) + state.Value
+
+	result := Result{
+		Limit: sw.Requests,
+		Reset: sw.Window - elapsed,
+	}
+
+	if count+1 <= limit {
+		state.Value++
+		result.Allowed = true
+		result.Remaining = int(limit - count - 1)
+	} else if state.Value+1 <= limit && state.Prev > 0 {
+		x := 1 - (limit-state.Value-1)/state.Prev
+		result.RetryAfter = time.Duration(x*float64(sw.Window)) - elapsed
+	} else {
+		result.RetryAfter = sw.Window - elapsed
+	}
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_TokenBucket(t *testing.T) {
+	tb := TokenBucket{Burst: 2, Interval: 2 * time.Second}
+
+	var state State
+
+	now := time.Unix(1000, 0)
+
+	t.Run("burst", func(t *testing.T) {
+		r := tb.Take(&state, now)
+		assert.True(t, r.Allowed && r.Limit == 2 && r.Remaining == 1 && r.Reset == time.Second)
+
+		r = tb.Take(&state, now)
+		assert.True(t, r.Allowed && r.Remaining == 0 && r.Reset == 2*time.Second)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		r := tb.Take(&state, now.Add(500*time.Millisecond))
+		assert.True(t, !r.Allowed && r.Remaining == 0 && r.RetryAfter == 500*time.Millisecond)
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		r := tb.Take(&state, now.Add(time.Second))
+		assert.True(t, r.Allowed && r.Remaining == 0)
+
+		r = tb.Take(&state, now.Add(time.Hour))
+		assert.True(t, r.Allowed && r.Remaining == 1)
+	})
+}
+
+func Test_SlidingWindow(t *testing.T) {
+	sw := SlidingWindow{Requests: 2, Window: 10 * time.Second}
+
+	var state State
+
+	now := time.Unix(1000, 0)
+
+	t.Run("window", func(t *testing.T) {
+		r := sw.Take(&state, now)
+		assert.True(t, r.Allowed && r.Limit == 2 && r.Remaining == 1 && r.Reset == 10*time.Second)
+
+		r = sw.Take(&state, now.Add(time.Second))
+		assert.True(t, r.Allowed && r.Remaining == 0)
+
+		r = sw.Take(&state, now.Add(2*time.Second))
+		assert.True(t, !r.Allowed && r.RetryAfter == 8*time.Second)
+	})
+
+	t.Run("weighted_previous", func(t *testing.T) {
+		r := sw.Take(&state, now.Add(12*time.Second))
+		assert.True(t, !r.Allowed && r.RetryAfter == 3*time.Second)
+
+		r = sw.Take(&state, now.Add(15*time.Second))
+		assert.True(t, r.Allowed && r.Remaining == 0)
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		r := sw.Take(&state, now.Add(time.Minute))
+		assert.True(t, r.Allowed && r.Remaining == 1)
+	})
+}
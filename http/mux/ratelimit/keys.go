@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+// returns false when the request should not be limited
+type KeyFunc func(r *http.Request) (string, bool)
+
+// trustedHeader (e.g. X-Real-IP or X-Forwarded-For) must only be set behind a proxy that overwrites
+// or appends to it, the rightmost address is used since the ones before it are sent by the client
+func ByIP(trustedHeader string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		if values := r.Header.Values(trustedHeader); trustedHeader != "" && len(values) > 0 {
+			value := values[len(values)-1]
+
+			if i := strings.LastIndexByte(value, ','); i >= 0 {
+				value = value[i+1:]
+			}
+
+			if value = strings.TrimSpace(value); value != "" {
+				return "ip:" + value, true
+			}
+		}
+
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		return "ip:" + host, host != ""
+	}
+}
+
+func ByParam(name string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		value, ok := mux.LookupParam(r, name)
+
+		return "param:" + name + ":" + value, ok
+	}
+}
+
+func ByHeader(name string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		value := r.Header.Get(name)
+
+		return "header:" + name + ":" + value, value != ""
+	}
+}
+
+func BySubject(subject func(r *http.Request) string) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		value := subject(r)
+
+		return "subject:" + value, value != ""
+	}
+}
+
+func FirstOf(keys ...KeyFunc) KeyFunc {
+	return func(r *http.Request) (string, bool) {
+		for _, key := range keys {
+			if value, ok := key(r); ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+const (
+	LimitHeader     = "RateLimit-Limit"
+	RemainingHeader = "RateLimit-Remaining"
+	ResetHeader     = "RateLimit-Reset"
+)
+
+type Config struct {
+	Algorithm Algorithm
+	Key       KeyFunc
+	// namespaces keys when several limiters share one store
+	Prefix               string
+	Store                Store
+	LimitExceededHandler http.Handler
+	// store errors let requests through when nil
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	Clock        func() time.Time
+}
+
+// panics on a misconfigured algorithm so it fails at startup rather than on traffic
+func Middleware(cfg *Config) mux.Middleware {
+	if cfg.Algorithm == nil {
+		panic("ratelimit: algorithm must not be nil")
+	}
+	if cfg.Algorithm.Limit() <= 0 || cfg.Algorithm.TTL() <= 0 {
+		panic(fmt.Sprintf("ratelimit: invalid algorithm %+v, limit and interval must be positive", cfg.Algorithm))
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryStore(0)
+	}
+
+	key := cfg.Key
+	if key == nil {
+		key = ByIP("")
+	}
+
+	exceeded := cfg.LimitExceededHandler
+	if exceeded == nil {
+		exceeded = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		})
+	}
+
+	clock := cfg.Clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			k, ok := key(r)
+
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			result, err := store.Take(r.Context(), cfg.Prefix+k, cfg.Algorithm, clock())
+
+			if err != nil {
+				if cfg.ErrorHandler != nil {
+					cfg.ErrorHandler(w, r, err)
+				} else {
+					next.ServeHTTP(w, r)
+				}
+				return
+			}
+
+			h := w.Header()
+
+			h.Set(LimitHeader, strconv.Itoa(result.Limit))
+			h.Set(RemainingHeader, strconv.Itoa(result.Remaining))
+			h.Set(ResetHeader, formatSeconds(result.Reset))
+
+			if !result.Allowed {
+				h.Set("Retry-After", formatSeconds(result.RetryAfter))
+
+				exceeded.ServeHTTP(w, r)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func formatSeconds(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, alg Algorithm, now time.Time) (Result, error) {
+	return Result{}, errors.New("unavailable")
+}
+
+func Test_Middleware(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	g := new(mux.Group)
+
+	g.Middleware(Middleware(&Config{
+		Algorithm: TokenBucket{Burst: 1, Interval: time.Minute},
+		Key:       FirstOf(ByHeader("X-User"), ByParam("topic")),
+		Clock:     func() time.Time { return now },
+	}))
+
+	g.Endpoint(http.MethodPost, "/forum/:topic", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	post := func(path, user string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path, nil)
+		if user != "" {
+			r.Header.Set("X-User", user)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		w := post("/forum/1", "alice")
+		assert.True(t, w.Code == http.StatusOK)
+		assert.True(t, w.Header().Get(LimitHeader) == "1")
+		assert.True(t, w.Header().Get(RemainingHeader) == "0")
+		assert.True(t, w.Header().Get(ResetHeader) == "60")
+	})
+
+	t.Run("exceeded", func(t *testing.T) {
+		w := post("/forum/2", "alice")
+		assert.True(t, w.Code == http.StatusTooManyRequests)
+		assert.True(t, w.Header().Get("Retry-After") == "60")
+	})
+
+	t.Run("other_keys", func(t *testing.T) {
+		assert.True(t, post("/forum/1", "bob").Code == http.StatusOK)
+		assert.True(t, post("/forum/1", "").Code == http.StatusOK)
+		assert.True(t, post("/forum/1", "").Code == http.StatusTooManyRequests)
+	})
+
+	t.Run("refilled", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		assert.True(t, post("/forum/2", "alice").Code == http.StatusOK)
+	})
+}
+
+func Test_StoreErrors(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("fail_open", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Middleware(&Config{Algorithm: TokenBucket{Burst: 1, Interval: time.Second}, Store: failingStore{}})(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, w.Code == http.StatusOK)
+	})
+
+	t.Run("error_handler", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		Middleware(&Config{
+			Algorithm: TokenBucket{Burst: 1, Interval: time.Second},
+			Store:     failingStore{},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+		})(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, w.Code == http.StatusServiceUnavailable)
+	})
+}
+
+func Test_InvalidConfig(t *testing.T) {
+	for name, alg := range map[string]Algorithm{
+		"nil":              nil,
+		"zero_interval":    TokenBucket{Burst: 1},
+		"zero_burst":       TokenBucket{Interval: time.Second},
+		"zero_window":      SlidingWindow{Requests: 1},
+		"negative_request": SlidingWindow{Requests: -1, Window: time.Second},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				assert.True(t, recover() != nil)
+			}()
+			Middleware(&Config{Algorithm: alg})
+		})
+	}
+}
+
+func Test_ByIP(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+
+	key, ok := ByIP("")(r)
+	assert.True(t, ok && key == "ip:10.0.0.1")
+
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, 5.6.7.8")
+
+	key, ok = ByIP("X-Forwarded-For")(r)
+	assert.True(t, ok && key == "ip:5.6.7.8")
+
+	r.Header.Add("X-Forwarded-For", "9.9.9.9")
+
+	key, ok = ByIP("X-Forwarded-For")(r)
+	assert.True(t, ok && key == "ip:9.9.9.9")
+
+	r.Header.Set("X-Forwarded-For", "1.2.3.4, ")
+
+	key, ok = ByIP("X-Forwarded-For")(r)
+	assert.True(t, ok && key == "ip:10.0.0.1")
+}
+
+func Test_MemoryStore(t *testing.T) {
+	s := NewMemoryStore(4)
+	alg := TokenBucket{Burst: 1, Interval: time.Second}
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < sweepInterval; i++ {
+		_, _ = s.Take(context.Background(), string(rune('a'+i%8)), alg, now)
+	}
+
+	assert.True(t, s.Len() == 8)
+
+	for i := 0; i < sweepInterval*4; i++ {
+		_, _ = s.Take(context.Background(), "z", alg, now.Add(time.Minute))
+	}
+
+	assert.True(t, s.Len() < 8)
+}
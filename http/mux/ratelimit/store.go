@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+type Store interface {
+	Take(ctx context.Context, key string, alg Algorithm, now time.Time) (Result, error)
+}
+
+const (
+	defaultShardCount = 32
+	sweepInterval     = 1024
+)
+
+type memoryEntry struct {
+	state   State
+	expires time.Time
+}
+
+type memoryShard struct {
+	mu      sync.Mutex
+	entries map[string]*memoryEntry
+	ops     int
+}
+
+type MemoryStore struct {
+	shards []memoryShard
+}
+
+func NewMemoryStore(shardCount int) *MemoryStore {
+	if shardCount <= 0 {
+		shardCount = defaultShardCount
+	}
+
+	s := &MemoryStore{shards: make([]memoryShard, shardCount)}
+
+	for i := range s.shards {
+		s.shards[i].entries = make(map[string]*memoryEntry)
+	}
+
+	return s
+}
+
+func (s *MemoryStore) Take(ctx context.Context, key string, alg Algorithm, now time.Time) (Result, error) {
+	shard := s.shard(key)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.ops++
+
+	if shard.ops%sweepInterval == 0 {
+		shard.sweep(now)
+	}
+
+	entry := shard.entries[key]
+
+	if entry == nil || now.After(entry.expires) {
+		entry = new(memoryEntry)
+
+		shard.entries[key] = entry
+	}
+
+	result := alg.Take(&entry.state, now)
+
+	entry.expires = now.Add(alg.TTL())
+
+	return result, nil
+}
+
+func (s *MemoryStore) Len() int {
+	n := 0
+
+	for i := range s.shards {
+		shard := &s.shards[i]
+
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+
+	return n
+}
+
+func (s *MemoryStore) shard(key string) *memoryShard {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return &s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+func (shard *memoryShard) sweep(now time.Time) {
+	for key, entry := range shard.entries {
+		if now.After(entry.expires) {
+			delete(shard.entries, key)
+		}
+	}
+}
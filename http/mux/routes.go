@@ -0,0 +1,85 @@
+package mux
+
+import "strings"
+
+type RouteParam struct {
+	Name       string
+	Constraint string
+	CatchAll   bool
+}
+
+type Route struct {
+	Method      string
+	Host        string
+	Path        string
+	Params      []RouteParam
+	Middlewares int
+	Name        string
+	Summary     string
+	Tags        []string
+	Endpoint    *Endpoint
+}
+
+func (hr *Router) Routes() []Route {
+	routes := make([]Route, len(hr.table))
+	copy(routes, hr.table)
+	return routes
+}
+
+func (hr *Router) addRoute(e *Endpoint, host, path string, middlewares int) {
+	segments, _ := parsePath(path, nil)
+
+	var params []RouteParam
+
+	for _, s := range segments {
+		if s.kind == staticSegment {
+			continue
+		}
+
+		param := RouteParam{
+			Name:     s.name,
+			CatchAll: s.kind == catchAllSegment,
+		}
+
+		if s.constraint != nil {
+			param.Constraint = s.constraint.pattern
+		}
+
+		params = append(params, param)
+	}
+
+	routePath := joinPath(hr.prefix, path)
+
+	if hasTrailingSlash(path, segments) {
+		routePath += "/"
+	}
+
+	hr.table = append(hr.table, Route{
+		Method:      e.Method,
+		Host:        host,
+		Path:        routePath,
+		Params:      params,
+		Middlewares: middlewares,
+		Name:        e.Name,
+		Summary:     e.Summary,
+		Tags:        e.Tags,
+		Endpoint:    e,
+	})
+}
+
+func joinPath(parts ...string) string {
+	var sb strings.Builder
+
+	for _, part := range parts {
+		for _, s := range strings.FieldsFunc(part, func(r rune) bool { return r == '/' }) {
+			sb.WriteByte('/')
+			sb.WriteString(s)
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "/"
+	}
+
+	return sb.String()
+}
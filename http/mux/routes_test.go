@@ -0,0 +1,61 @@
+package mux
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_Routes(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	emptyMiddleware := func(next http.Handler) http.Handler { return next }
+
+	g := new(Group)
+
+	g.Endpoint(http.MethodGet, "/works/:id<int>", emptyHandler).
+		WithName("work").
+		WithSummary("Work info").
+		WithTags("works").
+		WithMiddlewares(emptyMiddleware)
+
+	g.Subgroup(func(g *Group) {
+		g.Middleware(emptyMiddleware)
+		g.Middleware(emptyMiddleware)
+
+		g.Endpoint(http.MethodGet, "static//*filepath/", emptyHandler)
+		g.Endpoint(http.MethodGet, "/invalid/*x/y", emptyHandler)
+	})
+
+	router, _ := NewRouter(&Config{
+		RootGroup:         g,
+		NotFoundHandler:   http.NotFoundHandler(),
+		CommonPrefix:      "v1",
+		GlobalMiddlewares: []Middleware{emptyMiddleware},
+	})
+
+	routes := router.Routes()
+
+	assert.True(t, len(routes) == 2)
+
+	assert.DeepEqual(t, routes[0], Route{
+		Method:      http.MethodGet,
+		Path:        "/v1/works/:id<int>",
+		Params:      []RouteParam{{Name: "id", Constraint: "int"}},
+		Middlewares: 2,
+		Name:        "work",
+		Summary:     "Work info",
+		Tags:        []string{"works"},
+		Endpoint:    g.Endpoints[0],
+	})
+
+	assert.True(t, routes[1].Path == "/v1/static/*filepath")
+	assert.DeepEqual(t, routes[1].Params, []RouteParam{{Name: "filepath", CatchAll: true}})
+	assert.True(t, routes[1].Middlewares == 3)
+}
+
+func Test_JoinPath(t *testing.T) {
+	assert.True(t, joinPath() == "/")
+	assert.True(t, joinPath("", "/") == "/")
+	assert.True(t, joinPath("v1", "/x//y/") == "/v1/x/y")
+}
@@ -0,0 +1,329 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+var (
+	ErrNoMiddleware   = errors.New("session: middleware is not installed")
+	ErrCookieTooLarge = errors.New("session: cookie too large")
+)
+
+const (
+	DefaultCookieName = "session"
+	DefaultMaxAge     = 24 * time.Hour
+
+	maxCookieSize = 4096
+)
+
+// implemented by *signed.SealedCoder and *signed.Keyring
+type Codec interface {
+	Encode(input []byte) ([]byte, error)
+	Decode(input []byte) ([]byte, error)
+}
+
+type coderCodec struct {
+	coder *signed.Coder
+}
+
+func (c coderCodec) Encode(input []byte) ([]byte, error) {
+	return c.coder.Encode(input), nil
+}
+
+func (c coderCodec) Decode(input []byte) ([]byte, error) {
+	return c.coder.Decode(input)
+}
+
+func Signed(coder *signed.Coder) Codec {
+	return coderCodec{coder: coder}
+}
+
+type Config struct {
+	Codec      Codec
+	CookieName string
+	Domain     string
+	Path       string
+	// idle timeout, the cookie is reissued once half of it has passed
+	MaxAge time.Duration
+	// limits the lifetime of a session regardless of activity when set
+	AbsoluteTimeout time.Duration
+	// allows the cookie over plain HTTP
+	Insecure    bool
+	SameSite    http.SameSite
+	Revocations RevocationStore
+	Clock       func() time.Time
+	// reports errors of automatic saving, which happens after the handler has started the response
+	ErrorLog func(r *http.Request, err error)
+}
+
+type payload struct {
+	signed.Claims
+	Values map[string]string `json:"val,omitempty"`
+}
+
+type state struct {
+	cfg     *Config
+	r       *http.Request
+	w       http.ResponseWriter
+	session *Session
+	err     error
+}
+
+type stateKey struct{}
+
+func Middleware(cfg *Config) mux.Middleware {
+	c := *cfg
+
+	if c.CookieName == "" {
+		c.CookieName = DefaultCookieName
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = DefaultMaxAge
+	}
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+	if c.Clock == nil {
+		c.Clock = time.Now
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			st := &state{cfg: &c, w: w}
+
+			st.r = r.WithContext(context.WithValue(r.Context(), stateKey{}, st))
+
+			sw := &saveWriter{ResponseWriter: w, st: st}
+
+			next.ServeHTTP(sw, st.r)
+
+			sw.save()
+		})
+	}
+}
+
+// the session is decoded on first use, invalid, expired and revoked cookies start a new one
+func Load(ctx context.Context) (*Session, error) {
+	st, ok := ctx.Value(stateKey{}).(*state)
+	if !ok {
+		return nil, ErrNoMiddleware
+	}
+	return st.load()
+}
+
+// writes the cookie now, otherwise it is written before the response header
+func Save(ctx context.Context) error {
+	st, ok := ctx.Value(stateKey{}).(*state)
+	if !ok {
+		return ErrNoMiddleware
+	}
+	return st.save()
+}
+
+func (st *state) load() (*Session, error) {
+	if st.session != nil || st.err != nil {
+		return st.session, st.err
+	}
+
+	now := st.cfg.Clock()
+
+	s, err := st.decode(now)
+
+	if err == nil && s == nil {
+		s = newSession(now)
+	}
+
+	st.session, st.err = s, err
+
+	return s, err
+}
+
+func (st *state) decode(now time.Time) (*Session, error) {
+	cookie, err := st.r.Cookie(st.cfg.CookieName)
+	if err != nil {
+		return nil, nil
+	}
+
+	data, err := st.cfg.Codec.Decode([]byte(cookie.Value))
+	if err != nil {
+		return nil, nil
+	}
+
+	var p payload
+
+	if json.Unmarshal(data, &p) != nil || p.ID == "" {
+		return nil, nil
+	}
+
+	validator := signed.Validator{
+		RequireExpiry: true,
+		Clock:         func() time.Time { return now },
+	}
+
+	if validator.Validate(&p.Claims) != nil {
+		return nil, nil
+	}
+
+	createdAt := time.Unix(p.IssuedAt, 0)
+
+	if st.cfg.AbsoluteTimeout > 0 && !now.Before(createdAt.Add(st.cfg.AbsoluteTimeout)) {
+		return nil, nil
+	}
+
+	if st.cfg.Revocations != nil {
+		revoked, err := st.cfg.Revocations.IsRevoked(st.r.Context(), p.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, nil
+		}
+	}
+
+	if p.Values == nil {
+		p.Values = make(map[string]string)
+	}
+
+	return &Session{
+		id:        p.ID,
+		values:    p.Values,
+		createdAt: createdAt,
+		expiresAt: time.Unix(p.ExpiresAt, 0),
+	}, nil
+}
+
+func (st *state) save() error {
+	s := st.session
+
+	if s == nil {
+		return nil
+	}
+
+	ctx := st.r.Context()
+	now := st.cfg.Clock()
+
+	if st.cfg.Revocations != nil {
+		if s.revokedID != "" {
+			if err := st.cfg.Revocations.Revoke(ctx, s.revokedID, s.expiresAt); err != nil {
+				return err
+			}
+			s.revokedID = ""
+		}
+		if s.destroyed && !s.isNew {
+			if err := st.cfg.Revocations.Revoke(ctx, s.id, s.expiresAt); err != nil {
+				return err
+			}
+		}
+	}
+
+	if s.destroyed {
+		if !s.isNew {
+			http.SetCookie(st.w, st.cookie("", time.Unix(0, 0), -1))
+		}
+		st.session = newSession(now)
+		return nil
+	}
+
+	refresh := !s.isNew && now.After(s.expiresAt.Add(-st.cfg.MaxAge/2))
+
+	if !s.modified && !refresh {
+		return nil
+	}
+
+	s.expiresAt = now.Add(st.cfg.MaxAge)
+
+	if st.cfg.AbsoluteTimeout > 0 {
+		if deadline := s.createdAt.Add(st.cfg.AbsoluteTimeout); s.expiresAt.After(deadline) {
+			s.expiresAt = deadline
+		}
+	}
+
+	p := payload{Values: s.values}
+	p.ID = s.id
+	p.IssuedAt = s.createdAt.Unix()
+	p.ExpiresAt = s.expiresAt.Unix()
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	value, err := st.cfg.Codec.Encode(data)
+	if err != nil {
+		return err
+	}
+
+	cookie := st.cookie(string(value), s.expiresAt, int(s.expiresAt.Sub(now)/time.Second))
+
+	if len(cookie.String()) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+
+	http.SetCookie(st.w, cookie)
+
+	s.isNew = false
+	s.modified = false
+
+	return nil
+}
+
+func (st *state) cookie(value string, expires time.Time, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     st.cfg.CookieName,
+		Value:    value,
+		Path:     st.cfg.Path,
+		Domain:   st.cfg.Domain,
+		Expires:  expires,
+		MaxAge:   maxAge,
+		Secure:   !st.cfg.Insecure,
+		HttpOnly: true,
+		SameSite: st.cfg.SameSite,
+	}
+}
+
+type saveWriter struct {
+	http.ResponseWriter
+	st          *state
+	wroteHeader bool
+}
+
+func (w *saveWriter) save() {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if err := w.st.save(); err != nil && w.st.cfg.ErrorLog != nil {
+		w.st.cfg.ErrorLog(w.st.r, err)
+	}
+}
+
+func (w *saveWriter) WriteHeader(status int) {
+	w.save()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *saveWriter) Write(p []byte) (int, error) {
+	w.save()
+	return w.ResponseWriter.Write(p)
+}
+
+func (w *saveWriter) Flush() {
+	w.save()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *saveWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,39 @@
+package session
+
+import (
+	"context"
+	"time"
+
+	"github.com/FantLab/go-kit/database/sqlapi"
+)
+
+type RevocationStore interface {
+	// expiresAt is when the revoked session would have expired anyway
+	Revoke(ctx context.Context, id string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, id string) (bool, error)
+}
+
+// table (session_id VARCHAR PRIMARY KEY, expires_at DATETIME)
+type SQLRevocationStore struct {
+	db    sqlapi.DB
+	table string
+}
+
+func NewSQLRevocationStore(db sqlapi.DB, table string) *SQLRevocationStore {
+	return &SQLRevocationStore{db: db, table: table}
+}
+
+func (s *SQLRevocationStore) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	return s.db.Write(ctx, sqlapi.NewQuery("INSERT IGNORE INTO %s (session_id, expires_at) VALUES (?, ?)").Inject(s.table).WithArgs(id, expiresAt.UTC())).Error
+}
+
+func (s *SQLRevocationStore) IsRevoked(ctx context.Context, id string) (bool, error) {
+	var count int64
+	err := s.db.Read(ctx, sqlapi.NewQuery("SELECT COUNT(*) FROM %s WHERE session_id = ?").Inject(s.table).WithArgs(id), &count)
+	return count > 0, err
+}
+
+func (s *SQLRevocationStore) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := s.db.Write(ctx, sqlapi.NewQuery("DELETE FROM %s WHERE expires_at < ?").Inject(s.table).WithArgs(now.UTC()))
+	return result.RowsAffected, result.Error
+}
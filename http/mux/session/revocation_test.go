@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/database/sqlapi"
+	"github.com/FantLab/go-kit/database/sqlstubs"
+)
+
+func Test_SQLRevocationStore(t *testing.T) {
+	db := &sqlstubs.StubDB{
+		ReadTable:  make(map[string]interface{}),
+		WriteTable: make(map[string]sqlapi.Result),
+	}
+
+	store := NewSQLRevocationStore(db, "revoked_sessions")
+
+	ctx := context.Background()
+	expiresAt := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	db.WriteTable["INSERT IGNORE INTO revoked_sessions (session_id, expires_at) VALUES ('abc', '2020-05-01 12:00:00')"] = sqlapi.Result{RowsAffected: 1}
+	db.WriteTable["DELETE FROM revoked_sessions WHERE expires_at < '2020-05-02 00:00:00'"] = sqlapi.Result{RowsAffected: 3}
+	db.ReadTable["SELECT COUNT(*) FROM revoked_sessions WHERE session_id = 'abc'"] = int64(1)
+
+	t.Run("revoke", func(t *testing.T) {
+		assert.True(t, store.Revoke(ctx, "abc", expiresAt) == nil)
+	})
+
+	t.Run("is_revoked", func(t *testing.T) {
+		revoked, err := store.IsRevoked(ctx, "abc")
+		assert.True(t, err == nil && revoked)
+	})
+
+	t.Run("delete_expired", func(t *testing.T) {
+		n, err := store.DeleteExpired(ctx, time.Date(2020, 5, 2, 0, 0, 0, 0, time.UTC))
+		assert.True(t, err == nil && n == 3)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		errDB := errors.New("db")
+		db.WriteTable["INSERT IGNORE INTO revoked_sessions (session_id, expires_at) VALUES ('xyz', '2020-05-01 12:00:00')"] = sqlapi.Result{Error: errDB}
+		assert.True(t, store.Revoke(ctx, "xyz", expiresAt) == errDB)
+	})
+}
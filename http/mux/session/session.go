@@ -0,0 +1,92 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+)
+
+type Session struct {
+	id        string
+	values    map[string]string
+	createdAt time.Time
+	expiresAt time.Time
+	isNew     bool
+	modified  bool
+	destroyed bool
+	revokedID string
+}
+
+func newSession(now time.Time) *Session {
+	return &Session{
+		id:        newID(),
+		values:    make(map[string]string),
+		createdAt: now,
+		isNew:     true,
+	}
+}
+
+func (s *Session) ID() string {
+	return s.id
+}
+
+func (s *Session) IsNew() bool {
+	return s.isNew
+}
+
+func (s *Session) CreatedAt() time.Time {
+	return s.createdAt
+}
+
+func (s *Session) ExpiresAt() time.Time {
+	return s.expiresAt
+}
+
+func (s *Session) Get(key string) string {
+	return s.values[key]
+}
+
+func (s *Session) Lookup(key string) (string, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *Session) Set(key, value string) {
+	s.values[key] = value
+	s.modified = true
+}
+
+func (s *Session) Delete(key string) {
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.modified = true
+	}
+}
+
+// persists the session even if no values are set, e.g. when something is bound to its id
+func (s *Session) Touch() {
+	s.modified = true
+}
+
+// revokes the session and removes the cookie on save
+func (s *Session) Destroy() {
+	s.values = make(map[string]string)
+	s.destroyed = true
+}
+
+// issues a new id keeping the values and revokes the old one, call it on login
+func (s *Session) Regenerate() {
+	if !s.isNew && s.revokedID == "" {
+		s.revokedID = s.id
+	}
+	s.id = newID()
+	s.modified = true
+}
+
+func newID() string {
+	var b [18]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
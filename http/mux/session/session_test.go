@@ -0,0 +1,181 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/crypto/signed"
+	"github.com/FantLab/go-kit/http/mux"
+)
+
+type memoryRevocations map[string]time.Time
+
+func (m memoryRevocations) Revoke(ctx context.Context, id string, expiresAt time.Time) error {
+	m[id] = expiresAt
+	return nil
+}
+
+func (m memoryRevocations) IsRevoked(ctx context.Context, id string) (bool, error) {
+	_, ok := m[id]
+	return ok, nil
+}
+
+func Test_Session(t *testing.T) {
+	coder, _ := signed.Generate()
+	secret, _ := signed.GenerateSecret()
+	sealer, _ := signed.NewSealedCoder(coder, secret)
+
+	now := time.Unix(1000000, 0)
+	revocations := make(memoryRevocations)
+
+	g := new(mux.Group)
+
+	g.Middleware(Middleware(&Config{
+		Codec:           sealer,
+		MaxAge:          time.Hour,
+		AbsoluteTimeout: 24 * time.Hour,
+		Revocations:     revocations,
+		Clock:           func() time.Time { return now },
+	}))
+
+	g.Endpoint(http.MethodGet, "/whoami", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := Load(r.Context())
+		assert.True(t, err == nil)
+		_, _ = w.Write([]byte(s.Get("user")))
+	}))
+	g.Endpoint(http.MethodPost, "/login", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, _ := Load(r.Context())
+		s.Regenerate()
+		s.Set("user", "alice")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	g.Endpoint(http.MethodPost, "/logout", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, _ := Load(r.Context())
+		s.Destroy()
+		assert.True(t, Save(r.Context()) == nil)
+	}))
+	g.Endpoint(http.MethodGet, "/static", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	router, _ := mux.NewRouter(&mux.Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+	})
+
+	serve := func(method, path string, cookie *http.Cookie) (*http.Cookie, string) {
+		r := httptest.NewRequest(method, path, nil)
+		if cookie != nil {
+			r.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		cookies := w.Result().Cookies()
+		if len(cookies) == 0 {
+			return nil, w.Body.String()
+		}
+		return cookies[0], w.Body.String()
+	}
+
+	t.Run("anonymous", func(t *testing.T) {
+		cookie, body := serve(http.MethodGet, "/whoami", nil)
+		assert.True(t, cookie == nil && body == "")
+	})
+
+	cookie, _ := serve(http.MethodPost, "/login", nil)
+
+	t.Run("login", func(t *testing.T) {
+		assert.True(t, cookie != nil && cookie.Name == DefaultCookieName)
+		assert.True(t, cookie.HttpOnly && cookie.Secure && cookie.SameSite == http.SameSiteLaxMode && cookie.Path == "/")
+		assert.True(t, cookie.MaxAge == 3600)
+
+		_, body := serve(http.MethodGet, "/whoami", cookie)
+		assert.True(t, body == "alice")
+	})
+
+	t.Run("sliding", func(t *testing.T) {
+		now = now.Add(10 * time.Minute)
+		refreshed, body := serve(http.MethodGet, "/whoami", cookie)
+		assert.True(t, refreshed == nil && body == "alice")
+
+		now = now.Add(40 * time.Minute)
+		refreshed, body = serve(http.MethodGet, "/whoami", cookie)
+		assert.True(t, refreshed != nil && refreshed.MaxAge == 3600 && body == "alice")
+
+		now = now.Add(30 * time.Minute)
+		_, body = serve(http.MethodGet, "/whoami", cookie)
+		assert.True(t, body == "")
+		_, body = serve(http.MethodGet, "/whoami", refreshed)
+		assert.True(t, body == "alice")
+
+		cookie = refreshed
+	})
+
+	t.Run("untouched", func(t *testing.T) {
+		now = now.Add(59 * time.Minute)
+		refreshed, _ := serve(http.MethodGet, "/static", cookie)
+		assert.True(t, refreshed == nil)
+		now = now.Add(-59 * time.Minute)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		tampered := *cookie
+		b := []byte(tampered.Value)
+		b[len(b)/2] ^= 1
+		tampered.Value = string(b)
+		_, body := serve(http.MethodGet, "/whoami", &tampered)
+		assert.True(t, body == "")
+	})
+
+	t.Run("logout", func(t *testing.T) {
+		removed, _ := serve(http.MethodPost, "/logout", cookie)
+		assert.True(t, removed != nil && removed.MaxAge == -1 && removed.Value == "")
+		assert.True(t, len(revocations) == 1)
+
+		_, body := serve(http.MethodGet, "/whoami", cookie)
+		assert.True(t, body == "")
+	})
+
+	t.Run("regenerate_revokes", func(t *testing.T) {
+		first, _ := serve(http.MethodPost, "/login", nil)
+		second, _ := serve(http.MethodPost, "/login", first)
+
+		_, body := serve(http.MethodGet, "/whoami", first)
+		assert.True(t, body == "")
+		_, body = serve(http.MethodGet, "/whoami", second)
+		assert.True(t, body == "alice")
+	})
+
+	t.Run("absolute_timeout", func(t *testing.T) {
+		c, _ := serve(http.MethodPost, "/login", nil)
+		for i := 0; i < 46; i++ {
+			now = now.Add(31 * time.Minute)
+			if refreshed, _ := serve(http.MethodGet, "/whoami", c); refreshed != nil {
+				c = refreshed
+			}
+		}
+		_, body := serve(http.MethodGet, "/whoami", c)
+		assert.True(t, body == "alice")
+
+		now = now.Add(31 * time.Minute)
+		_, body = serve(http.MethodGet, "/whoami", c)
+		assert.True(t, body == "")
+	})
+}
+
+func Test_SignedCodec(t *testing.T) {
+	coder, _ := signed.Generate()
+
+	codec := Signed(coder)
+
+	x, err := codec.Encode([]byte("x"))
+	assert.True(t, err == nil)
+
+	y, err := codec.Decode(x)
+	assert.True(t, err == nil && string(y) == "x")
+
+	_, err = Load(context.Background())
+	assert.True(t, err == ErrNoMiddleware)
+}
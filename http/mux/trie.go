@@ -6,45 +6,67 @@ import (
 )
 
 type pathHandler struct {
-	path    string
-	handler http.Handler
+	path          string
+	handler       http.Handler
+	endpoint      *Endpoint
+	trailingSlash bool
+	catchAll      bool
+}
+
+type param struct {
+	key   string
+	value string
+}
+
+type nodeKey struct {
+	name   string
+	origin *pathHandler
 }
 
 type node struct {
-	value    *pathHandler
-	keys     map[string]struct{}
-	anyChild *node
-	children map[string]*node
+	value         *pathHandler
+	keys          []nodeKey
+	constraint    *paramConstraint
+	paramChildren []*node
+	catchAllChild *node
+	children      map[string]*node
 }
 
-func (n *node) insertPathHandler(path []string, value *pathHandler) {
+type conflictFunc func(err error, conflict *pathHandler)
+
+func (n *node) insertPathHandler(path []segment, value *pathHandler, onConflict conflictFunc) {
 	if len(path) == 0 {
+		if n.value != nil {
+			onConflict(ErrDuplicateRoute, n.value)
+		}
+
 		n.value = value
 
 		return
 	}
 
-	wildcard, name := parseSegment(path[0])
+	s := path[0]
 
 	var child *node
 
-	if wildcard {
-		child = n.anyChild
+	switch s.kind {
+	case paramSegment:
+		child = n.paramChild(s.constraint)
+
+		child.addKey(s.name, value, onConflict)
+	case catchAllSegment:
+		child = n.catchAllChild
 
 		if child == nil {
 			child = new(node)
 
-			n.anyChild = child
+			n.catchAllChild = child
 		}
 
-		if child.keys == nil {
-			child.keys = make(map[string]struct{})
-		}
-
-		child.keys[name] = struct{}{}
-	} else {
+		child.addKey(s.name, value, onConflict)
+	default:
 		if n.children != nil {
-			child = n.children[name]
+			child = n.children[s.name]
 		}
 
 		if child == nil {
@@ -54,111 +76,293 @@ func (n *node) insertPathHandler(path []string, value *pathHandler) {
 				n.children = make(map[string]*node)
 			}
 
-			n.children[name] = child
+			n.children[s.name] = child
 		}
 	}
 
-	child.insertPathHandler(path[1:], value)
+	child.insertPathHandler(path[1:], value, onConflict)
 }
 
-func (n *node) handlerForPath(path []string, saveParam func(key, value string)) *pathHandler {
-	if len(path) == 0 {
+// constrained params are matched before the unconstrained one
+func (n *node) paramChild(constraint *paramConstraint) *node {
+	for _, child := range n.paramChildren {
+		if child.constraint.equal(constraint) {
+			return child
+		}
+	}
+
+	child := &node{constraint: constraint}
+
+	if constraint == nil {
+		n.paramChildren = append(n.paramChildren, child)
+	} else {
+		i := len(n.paramChildren)
+		if i > 0 && n.paramChildren[i-1].constraint == nil {
+			i--
+		}
+		n.paramChildren = append(n.paramChildren, nil)
+		copy(n.paramChildren[i+1:], n.paramChildren[i:])
+		n.paramChildren[i] = child
+	}
+
+	return child
+}
+
+func (n *node) addKey(name string, value *pathHandler, onConflict conflictFunc) {
+	for _, key := range n.keys {
+		if key.name == name {
+			return
+		}
+	}
+
+	if len(n.keys) > 0 {
+		onConflict(ErrAmbiguousParam, n.keys[0].origin)
+	}
+
+	n.keys = append(n.keys, nodeKey{name: name, origin: value})
+}
+
+func (n *node) saveParams(value string, params *[]param) {
+	if params == nil {
+		return
+	}
+
+	for _, key := range n.keys {
+		*params = append(*params, param{key: key.name, value: value})
+	}
+}
+
+func (n *node) match(path string, params *[]param) *pathHandler {
+	path = trimLeadingSlashes(path)
+
+	if path == "" {
+		if n.value == nil && n.catchAllChild != nil {
+			return n.catchAllChild.matchRest(path, params)
+		}
 		return n.value
 	}
 
-	name, path := path[0], path[1:]
+	name, rest := path, ""
+
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		name, rest = path[:i], path[i:]
+	}
 
 	if child := n.children[name]; child != nil {
-		if value := child.handlerForPath(path, saveParam); value != nil {
+		if value := child.match(rest, params); value != nil {
 			return value
 		}
 	}
 
-	if n.anyChild != nil {
-		if value := n.anyChild.handlerForPath(path, saveParam); value != nil {
-			for key := range n.anyChild.keys {
-				saveParam(key, name)
-			}
+	for _, child := range n.paramChildren {
+		if !child.constraint.match(name) {
+			continue
+		}
+		if value := child.match(rest, params); value != nil {
+			child.saveParams(name, params)
 			return value
 		}
 	}
 
+	if n.catchAllChild != nil {
+		return n.catchAllChild.matchRest(path, params)
+	}
+
 	return nil
 }
 
-func parseSegment(s string) (bool, string) {
-	if s[0] == ':' {
-		return true, s[1:]
+func (n *node) matchRest(path string, params *[]param) *pathHandler {
+	if n.value == nil {
+		return nil
+	}
+
+	path = strings.TrimRight(path, "/")
+
+	if strings.Contains(path, "//") {
+		path = strings.Join(strings.FieldsFunc(path, func(r rune) bool {
+			return r == '/'
+		}), "/")
+	}
+
+	n.saveParams(path, params)
+
+	return n.value
+}
+
+func (n *node) handlerForPathFold(path []string, corrected []string) (*pathHandler, []string) {
+	if len(path) == 0 {
+		if n.value == nil && n.catchAllChild != nil {
+			return n.catchAllChild.value, corrected
+		}
+		return n.value, corrected
+	}
+
+	name, rest := path[0], path[1:]
+
+	for key, child := range n.children {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		if value, c := child.handlerForPathFold(rest, append(corrected, key)); value != nil {
+			return value, c
+		}
+	}
+
+	for _, child := range n.paramChildren {
+		if !child.constraint.match(name) {
+			continue
+		}
+		if value, c := child.handlerForPathFold(rest, append(corrected, name)); value != nil {
+			return value, c
+		}
+	}
+
+	if n.catchAllChild != nil && n.catchAllChild.value != nil {
+		return n.catchAllChild.value, append(corrected, path...)
+	}
+
+	return nil, nil
+}
+
+type segmentKind int
+
+const (
+	staticSegment segmentKind = iota
+	paramSegment
+	catchAllSegment
+)
+
+type segment struct {
+	kind       segmentKind
+	name       string
+	constraint *paramConstraint
+}
+
+func parseSegment(s string) (segment, bool) {
+	switch s[0] {
+	case ':':
+		name, pattern := s[1:], ""
+
+		if i := strings.IndexByte(name, '<'); i >= 0 {
+			if name[len(name)-1] != '>' {
+				return segment{}, false
+			}
+			name, pattern = name[:i], name[i+1:len(name)-1]
+		}
+
+		constraint, ok := newParamConstraint(pattern)
+
+		return segment{kind: paramSegment, name: name, constraint: constraint}, ok && name != ""
+	case '*':
+		return segment{kind: catchAllSegment, name: s[1:]}, len(s) > 1
+	}
+	return segment{kind: staticSegment, name: s}, true
+}
+
+func parsePath(path string, segmentValidator func(string) bool) ([]segment, bool) {
+	rawSegments := strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/'
+	})
+
+	segments := make([]segment, len(rawSegments))
+
+	for i, rawSegment := range rawSegments {
+		s, ok := parseSegment(rawSegment)
+
+		if ok && s.kind == catchAllSegment {
+			ok = i == len(rawSegments)-1
+		}
+		if ok && s.kind == staticSegment && segmentValidator != nil {
+			ok = segmentValidator(s.name)
+		}
+
+		if !ok {
+			return nil, false
+		}
+
+		segments[i] = s
 	}
-	return false, s
+
+	return segments, true
+}
+
+// catch-all routes ignore the trailing slash
+func hasTrailingSlash(path string, segments []segment) bool {
+	return len(segments) > 0 && segments[len(segments)-1].kind != catchAllSegment && strings.HasSuffix(path, "/")
 }
 
 type trie struct {
-	maxDepth         int
 	prefix           string
 	segmentValidator func(string) bool
 	root             *node
 }
 
-func (t *trie) insertPathHandler(path string, handler http.Handler) bool {
+func (t *trie) insertPathHandler(path string, handler http.Handler, endpoint *Endpoint) RouteErrors {
 	if handler == nil {
-		return false
+		return RouteErrors{{Err: ErrNilHandler, Endpoint: endpoint}}
 	}
 
-	segments := strings.FieldsFunc(path, func(r rune) bool {
-		return r == '/'
-	})
+	segments, ok := parsePath(path, t.segmentValidator)
 
-	if t.segmentValidator != nil {
-		for _, segment := range segments {
-			wildcard, name := parseSegment(segment)
-			if wildcard {
-				continue
-			}
-			if !t.segmentValidator(name) {
-				return false
-			}
-		}
+	if !ok {
+		return RouteErrors{{Err: ErrInvalidPath, Endpoint: endpoint}}
 	}
 
+	var errs RouteErrors
+
 	t.root.insertPathHandler(segments, &pathHandler{
-		path:    path,
-		handler: handler,
+		path:          path,
+		handler:       handler,
+		endpoint:      endpoint,
+		trailingSlash: hasTrailingSlash(path, segments),
+		catchAll:      len(segments) > 0 && segments[len(segments)-1].kind == catchAllSegment,
+	}, func(err error, conflict *pathHandler) {
+		errs = append(errs, &RouteError{
+			Err:      err,
+			Endpoint: endpoint,
+			Conflict: conflict.endpoint,
+		})
 	})
 
-	if len(segments) > t.maxDepth {
-		t.maxDepth = len(segments)
+	return errs
+}
+
+func (t *trie) match(path string, params *[]param) *pathHandler {
+	if t.prefix != "" {
+		path = trimLeadingSlashes(path)
+
+		if !strings.HasPrefix(path, t.prefix) || (len(path) > len(t.prefix) && path[len(t.prefix)] != '/') {
+			return nil
+		}
+
+		path = path[len(t.prefix):]
 	}
 
-	return true
+	return t.root.match(path, params)
 }
 
-func (t *trie) handlerForPath(path string) (*pathHandler, map[string]string) {
+func (t *trie) handlerForPathFold(path string) (*pathHandler, string) {
 	segments := strings.FieldsFunc(path, func(r rune) bool {
 		return r == '/'
 	})
 
+	corrected := make([]string, 0, len(segments))
+
 	if t.prefix != "" {
-		if len(segments) == 0 || t.prefix != segments[0] {
-			return nil, nil
+		if len(segments) == 0 || !strings.EqualFold(t.prefix, segments[0]) {
+			return nil, ""
 		}
+		corrected = append(corrected, t.prefix)
 		segments = segments[1:]
 	}
 
-	if len(segments) > t.maxDepth {
-		return nil, nil
-	}
-
-	var params map[string]string
+	value, corrected := t.root.handlerForPathFold(segments, corrected)
 
-	handler := t.root.handlerForPath(segments, func(key, value string) {
-		if params == nil {
-			params = make(map[string]string)
-		}
-		params[key] = value
-	})
+	if value == nil {
+		return nil, ""
+	}
 
-	return handler, params
+	return value, "/" + strings.Join(corrected, "/")
 }
 
 func newPathTrie(prefix string, segmentValidator func(string) bool) *trie {
@@ -168,3 +372,10 @@ func newPathTrie(prefix string, segmentValidator func(string) bool) *trie {
 		root:             &node{},
 	}
 }
+
+func trimLeadingSlashes(path string) string {
+	for len(path) > 0 && path[0] == '/' {
+		path = path[1:]
+	}
+	return path
+}
@@ -20,90 +20,90 @@ func Test_Trie(t *testing.T) {
 		{
 			trie.insertPathHandler("x/y/z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "1")
-			}))
+			}), nil)
 
 			trie.insertPathHandler("x/y/:z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "2")
-			}))
+			}), nil)
 
 			trie.insertPathHandler("x/:y/z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "3")
-			}))
+			}), nil)
 
 			trie.insertPathHandler("x/:y/:z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "4")
-			}))
+			}), nil)
 
 			trie.insertPathHandler(":x/y/z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "5")
-			}))
+			}), nil)
 
 			trie.insertPathHandler(":x/y/:z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "6")
-			}))
+			}), nil)
 
 			trie.insertPathHandler(":x/:y/z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "7")
-			}))
+			}), nil)
 
 			trie.insertPathHandler(":x/:y/:z", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, "8")
-			}))
+			}), nil)
 		}
 
 		cases := []func(w *httptest.ResponseRecorder){
 			// 1
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("x/y/z")
+				value, params := lookupTestPath(trie, "x/y/z")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "1\n")
 				assert.True(t, len(params) == 0)
 			},
 			// 2
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("x/y/9")
+				value, params := lookupTestPath(trie, "x/y/9")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "2\n")
 				assert.True(t, len(params) == 1 && params["z"] == "9")
 			},
 			// 3
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("x/9/z")
+				value, params := lookupTestPath(trie, "x/9/z")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "3\n")
 				assert.True(t, len(params) == 1 && params["y"] == "9")
 			},
 			// 4
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("x/9/10")
+				value, params := lookupTestPath(trie, "x/9/10")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "4\n")
 				assert.True(t, len(params) == 2 && params["y"] == "9" && params["z"] == "10")
 			},
 			// 5
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("1/y/z")
+				value, params := lookupTestPath(trie, "1/y/z")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "5\n")
 				assert.True(t, len(params) == 1 && params["x"] == "1")
 			},
 			// 6
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("1/y/2")
+				value, params := lookupTestPath(trie, "1/y/2")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "6\n")
 				assert.True(t, len(params) == 2 && params["x"] == "1" && params["z"] == "2")
 			},
 			// 7
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("1/2/z")
+				value, params := lookupTestPath(trie, "1/2/z")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "7\n")
 				assert.True(t, len(params) == 2 && params["x"] == "1" && params["y"] == "2")
 			},
 			// 8
 			func(rr *httptest.ResponseRecorder) {
-				value, params := trie.handlerForPath("1/2/3")
+				value, params := lookupTestPath(trie, "1/2/3")
 				value.handler.ServeHTTP(rr, nil)
 				assert.True(t, rr.Body.String() == "8\n")
 				assert.True(t, len(params) == 3 && params["x"] == "1" && params["y"] == "2" && params["z"] == "3")
@@ -126,26 +126,29 @@ func Test_EdgeCases(t *testing.T) {
 	t.Run("insert nil handler", func(t *testing.T) {
 		trie := newPathTrie("", nil)
 
-		assert.True(t, !trie.insertPathHandler("a/b", nil))
+		errs := trie.insertPathHandler("a/b", nil, nil)
+		assert.True(t, len(errs) == 1 && errs[0].Err == ErrNilHandler)
 	})
 
 	t.Run("existing handler", func(t *testing.T) {
 		trie := newPathTrie("", nil)
 
-		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler))
-		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler))
+		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler, nil) == nil)
+
+		errs := trie.insertPathHandler("x/y/z", emptyHandler, nil)
+		assert.True(t, len(errs) == 1 && errs[0].Err == ErrDuplicateRoute)
 	})
 
 	t.Run("not found handler", func(t *testing.T) {
 		trie := newPathTrie("", nil)
-		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler))
+		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler, nil) == nil)
 
 		{
-			value, params := trie.handlerForPath("x/y")
+			value, params := lookupTestPath(trie, "x/y")
 			assert.True(t, value == nil && params == nil)
 		}
 		{
-			value, params := trie.handlerForPath("a/b/c/d")
+			value, params := lookupTestPath(trie, "a/b/c/d")
 			assert.True(t, value == nil && params == nil)
 		}
 	})
@@ -160,9 +163,19 @@ func Test_EdgeCases(t *testing.T) {
 			return true
 		})
 
-		assert.True(t, !trie.insertPathHandler("x/ y/z", emptyHandler))
-		assert.True(t, trie.insertPathHandler("q/w/e", emptyHandler))
-		assert.True(t, !trie.insertPathHandler("q/w/e/r/ ", emptyHandler))
+		errs := trie.insertPathHandler("x/ y/z", emptyHandler, nil)
+		assert.True(t, len(errs) == 1 && errs[0].Err == ErrInvalidPath)
+		assert.True(t, trie.insertPathHandler("q/w/e", emptyHandler, nil) == nil)
+		assert.True(t, trie.insertPathHandler("q/w/e/r/ ", emptyHandler, nil) != nil)
+	})
+
+	t.Run("empty param name", func(t *testing.T) {
+		trie := newPathTrie("", nil)
+
+		for _, path := range []string{"/a/:", "/b/*", "/c/:<int>"} {
+			errs := trie.insertPathHandler(path, emptyHandler, nil)
+			assert.True(t, len(errs) == 1 && errs[0].Err == ErrInvalidPath)
+		}
 	})
 
 	t.Run("prefix", func(t *testing.T) {
@@ -170,14 +183,14 @@ func Test_EdgeCases(t *testing.T) {
 			return true
 		})
 
-		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler))
+		assert.True(t, trie.insertPathHandler("x/y/z", emptyHandler, nil) == nil)
 
 		{
-			value, params := trie.handlerForPath("x/y/z")
+			value, params := lookupTestPath(trie, "x/y/z")
 			assert.True(t, value == nil && params == nil)
 		}
 		{
-			value, params := trie.handlerForPath("v1/x/y/z")
+			value, params := lookupTestPath(trie, "v1/x/y/z")
 			assert.True(t, value != nil && params == nil)
 		}
 	})
@@ -187,10 +200,13 @@ func Test_EdgeCases(t *testing.T) {
 			return true
 		})
 
-		assert.True(t, trie.insertPathHandler("x/:y1/z", emptyHandler))
-		assert.True(t, trie.insertPathHandler("x/:y2/z", emptyHandler))
+		assert.True(t, trie.insertPathHandler("x/:y1/z", emptyHandler, nil) == nil)
+
+		errs := trie.insertPathHandler("x/:y2/z", emptyHandler, nil)
+		assert.True(t, len(errs.Filter(ErrAmbiguousParam)) == 1)
+		assert.True(t, len(errs.Filter(ErrDuplicateRoute)) == 1)
 
-		value, params := trie.handlerForPath("x/y/z")
+		value, params := lookupTestPath(trie, "x/y/z")
 		assert.True(t, value != nil)
 		assert.DeepEqual(t, params, map[string]string{
 			"y1": "y",
@@ -203,8 +219,8 @@ func Test_EdgeCases(t *testing.T) {
 			trie := newPathTrie("", func(s string) bool {
 				return true
 			})
-			assert.True(t, trie.insertPathHandler("/", emptyHandler))
-			value, params := trie.handlerForPath("/")
+			assert.True(t, trie.insertPathHandler("/", emptyHandler, nil) == nil)
+			value, params := lookupTestPath(trie, "/")
 			assert.True(t, value != nil && params == nil)
 			assert.True(t, trie.root.value != nil)
 		}
@@ -213,10 +229,210 @@ func Test_EdgeCases(t *testing.T) {
 			trie := newPathTrie("v1", func(s string) bool {
 				return true
 			})
-			assert.True(t, trie.insertPathHandler("/", emptyHandler))
-			value, params := trie.handlerForPath("v1/")
+			assert.True(t, trie.insertPathHandler("/", emptyHandler, nil) == nil)
+			value, params := lookupTestPath(trie, "v1/")
 			assert.True(t, value != nil && params == nil)
 			assert.True(t, trie.root.value != nil)
 		}
 	})
 }
+
+func Test_CatchAll(t *testing.T) {
+	makeHandler := func(s string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, s)
+		})
+	}
+
+	trie := newPathTrie("", func(s string) bool {
+		return true
+	})
+
+	assert.True(t, trie.insertPathHandler("static/*filepath", makeHandler("static"), nil) == nil)
+	assert.True(t, trie.insertPathHandler("static/favicon.ico", makeHandler("favicon"), nil) == nil)
+	assert.True(t, trie.insertPathHandler("static/:name/info", makeHandler("info"), nil) == nil)
+	assert.True(t, trie.insertPathHandler("proxy/:service/*rest", makeHandler("proxy"), nil) == nil)
+	assert.True(t, trie.insertPathHandler("files/*rest/x", makeHandler("bad"), nil) != nil)
+
+	serve := func(path string) (string, map[string]string) {
+		value, params := lookupTestPath(trie, path)
+		if value == nil {
+			return "", params
+		}
+		rr := httptest.NewRecorder()
+		value.handler.ServeHTTP(rr, nil)
+		return rr.Body.String(), params
+	}
+
+	t.Run("static priority", func(t *testing.T) {
+		body, params := serve("static/favicon.ico")
+		assert.True(t, body == "favicon" && params == nil)
+	})
+
+	t.Run("param priority", func(t *testing.T) {
+		body, params := serve("static/x/info")
+		assert.True(t, body == "info")
+		assert.DeepEqual(t, params, map[string]string{"name": "x"})
+	})
+
+	t.Run("rest", func(t *testing.T) {
+		{
+			body, params := serve("static/css/site/main.css")
+			assert.True(t, body == "static")
+			assert.DeepEqual(t, params, map[string]string{"filepath": "css/site/main.css"})
+		}
+		{
+			body, params := serve("static/x/info/more")
+			assert.True(t, body == "static")
+			assert.DeepEqual(t, params, map[string]string{"filepath": "x/info/more"})
+		}
+		{
+			body, params := serve("static/x")
+			assert.True(t, body == "static")
+			assert.DeepEqual(t, params, map[string]string{"filepath": "x"})
+		}
+		{
+			body, params := serve("static/")
+			assert.True(t, body == "static")
+			assert.DeepEqual(t, params, map[string]string{"filepath": ""})
+		}
+	})
+
+	t.Run("param and rest", func(t *testing.T) {
+		body, params := serve("proxy/users/a/b/c/d/e/f")
+		assert.True(t, body == "proxy")
+		assert.DeepEqual(t, params, map[string]string{"service": "users", "rest": "a/b/c/d/e/f"})
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		body, _ := serve("proxy")
+		assert.True(t, body == "")
+	})
+}
+
+func lookupTestPath(t *trie, path string) (*pathHandler, map[string]string) {
+	var params []param
+
+	value := t.match(path, &params)
+
+	if len(params) == 0 {
+		return value, nil
+	}
+
+	m := make(map[string]string, len(params))
+	for _, p := range params {
+		m[p.key] = p.value
+	}
+
+	return value, m
+}
+
+func Test_MatchAllocations(t *testing.T) {
+	router, _ := makeBenchmarkRouter()
+
+	w := new(discardResponseWriter)
+
+	for _, path := range []string{"/v1/forums", "/v1/forums/1/topics/2", "/v1/a/b/c/d/e/f/g/h"} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			router.ServeHTTP(w, r)
+		})
+
+		// request copy and route context only
+		assert.True(t, allocs <= 2)
+	}
+
+	trie := router.hosts[""][http.MethodGet]
+	params := make([]param, 0, 8)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		params = params[:0]
+		trie.match("/v1/forums/1/topics/2", &params)
+	})
+
+	assert.True(t, allocs == 0)
+}
+
+func Benchmark_Match(b *testing.B) {
+	router, _ := makeBenchmarkRouter()
+
+	trie := router.hosts[""][http.MethodGet]
+
+	for name, path := range map[string]string{
+		"static": "/v1/forums",
+		"param":  "/v1/forums/1/topics/2",
+		"deep":   "/v1/a/b/c/d/e/f/g/h",
+	} {
+		b.Run(name, func(b *testing.B) {
+			params := make([]param, 0, 8)
+
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				params = params[:0]
+				trie.match(path, &params)
+			}
+		})
+	}
+}
+
+func Benchmark_Router(b *testing.B) {
+	router, _ := makeBenchmarkRouter()
+
+	w := new(discardResponseWriter)
+
+	for name, path := range map[string]string{
+		"static": "/v1/forums",
+		"param":  "/v1/forums/1/topics/2",
+		"deep":   "/v1/a/b/c/d/e/f/g/h",
+	} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				router.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+func makeBenchmarkRouter() (*Router, RouteErrors) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	g := new(Group)
+
+	g.Endpoint(http.MethodGet, "/forums", emptyHandler)
+	g.Endpoint(http.MethodGet, "/forums/:forum_id", emptyHandler)
+	g.Endpoint(http.MethodGet, "/forums/:forum_id/topics", emptyHandler)
+	g.Endpoint(http.MethodGet, "/forums/:forum_id/topics/:topic_id", emptyHandler)
+	g.Endpoint(http.MethodGet, "/works/:id<int>", emptyHandler)
+	g.Endpoint(http.MethodGet, "/a/b/c/d/e/f/g/h", emptyHandler)
+	g.Endpoint(http.MethodGet, "/a/b/c/d/e/f/g/:x", emptyHandler)
+	g.Endpoint(http.MethodPost, "/forums/:forum_id/topics", emptyHandler)
+
+	return NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+		CommonPrefix:    "v1",
+	})
+}
+
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
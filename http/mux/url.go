@@ -0,0 +1,104 @@
+package mux
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+type namedRoute struct {
+	endpoint      *Endpoint
+	segments      []segment
+	trailingSlash bool
+}
+
+func (hr *Router) addNamedRoute(e *Endpoint, path string) *RouteError {
+	if other := hr.routes[e.Name]; other != nil {
+		return &RouteError{Err: ErrDuplicateName, Endpoint: e, Conflict: other.endpoint}
+	}
+
+	segments, _ := parsePath(path, hr.segmentValidator)
+
+	hr.routes[e.Name] = &namedRoute{
+		endpoint:      e,
+		segments:      segments,
+		trailingSlash: hasTrailingSlash(path, segments),
+	}
+
+	return nil
+}
+
+func (hr *Router) URL(name string, params ...string) (string, error) {
+	route := hr.routes[name]
+
+	if route == nil {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, name)
+	}
+
+	if len(params)%2 != 0 {
+		return "", fmt.Errorf("%w: %s", ErrOddParams, params[len(params)-1])
+	}
+
+	values := make(map[string]string, len(params)/2)
+
+	for i := 0; i < len(params); i += 2 {
+		values[params[i]] = params[i+1]
+	}
+
+	var sb strings.Builder
+
+	if hr.prefix != "" {
+		sb.WriteByte('/')
+		sb.WriteString(hr.prefix)
+	}
+
+	for _, s := range route.segments {
+		if s.kind == staticSegment {
+			sb.WriteByte('/')
+			sb.WriteString(s.name)
+			continue
+		}
+
+		value, ok := values[s.name]
+
+		if !ok || (value == "" && s.kind == paramSegment) {
+			return "", fmt.Errorf("%w: %s", ErrMissingParam, s.name)
+		}
+
+		if s.kind == paramSegment {
+			if !s.constraint.match(value) || !hr.validSegment(value) {
+				return "", fmt.Errorf("%w: %s", ErrInvalidParam, s.name)
+			}
+			sb.WriteByte('/')
+			sb.WriteString(url.PathEscape(value))
+			continue
+		}
+
+		if value == "" {
+			sb.WriteByte('/')
+			continue
+		}
+
+		for _, part := range strings.Split(value, "/") {
+			if !hr.validSegment(part) {
+				return "", fmt.Errorf("%w: %s", ErrInvalidParam, s.name)
+			}
+			sb.WriteByte('/')
+			sb.WriteString(url.PathEscape(part))
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "/", nil
+	}
+
+	if route.trailingSlash {
+		sb.WriteByte('/')
+	}
+
+	return sb.String(), nil
+}
+
+func (hr *Router) validSegment(s string) bool {
+	return s != "" && (hr.segmentValidator == nil || hr.segmentValidator(s))
+}
@@ -0,0 +1,133 @@
+package mux
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+)
+
+func Test_URLStrictTrailingSlash(t *testing.T) {
+	g := new(Group)
+
+	g.Endpoint(http.MethodGet, "/a/b/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).WithName("ab")
+
+	router, _ := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+		PathPolicy:      PathStrict,
+	})
+
+	u, err := router.URL("ab")
+	assert.True(t, err == nil && u == "/a/b/")
+
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, u, nil))
+	assert.True(t, rr.Code == http.StatusOK)
+
+	assert.True(t, router.Routes()[0].Path == "/a/b/")
+}
+
+func Test_URL(t *testing.T) {
+	emptyHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	g := new(Group)
+
+	g.Endpoint(http.MethodGet, "/", emptyHandler).WithName("index")
+	g.Endpoint(http.MethodGet, "/works/:id<int>/info", emptyHandler).WithName("work")
+	g.Endpoint(http.MethodGet, "/forums/:forum_id/topics/:topic_id", emptyHandler).WithName("topic")
+	g.Endpoint(http.MethodGet, "/static/*filepath", emptyHandler).WithName("static")
+	g.Endpoint(http.MethodGet, "/authors/:id/", emptyHandler).WithName("author")
+	g.Endpoint(http.MethodPost, "/works/:id/info", emptyHandler).WithName("work")
+	g.Endpoint(http.MethodGet, "/bad/ /path", emptyHandler).WithName("bad")
+
+	router, errs := NewRouter(&Config{
+		RootGroup:       g,
+		NotFoundHandler: http.NotFoundHandler(),
+		CommonPrefix:    "v1",
+		PathSegmentValidator: func(s string) bool {
+			for _, r := range s {
+				if r == ' ' || r == '?' {
+					return false
+				}
+			}
+			return true
+		},
+	})
+
+	t.Run("diagnostics", func(t *testing.T) {
+		assert.True(t, len(errs) == 2)
+		assert.True(t, len(errs.Filter(ErrInvalidPath)) == 1)
+
+		duplicates := errs.Filter(ErrDuplicateName)
+		assert.True(t, len(duplicates) == 1 && duplicates[0].Conflict.Method == http.MethodGet)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		{
+			u, err := router.URL("index")
+			assert.True(t, err == nil && u == "/v1")
+		}
+		{
+			u, err := router.URL("work", "id", "42")
+			assert.True(t, err == nil && u == "/v1/works/42/info")
+		}
+		{
+			u, err := router.URL("topic", "topic_id", "2", "forum_id", "1")
+			assert.True(t, err == nil && u == "/v1/forums/1/topics/2")
+		}
+		{
+			u, err := router.URL("topic", "topic_id", "ж", "forum_id", "1")
+			assert.True(t, err == nil && u == "/v1/forums/1/topics/%D0%B6")
+		}
+		{
+			u, err := router.URL("static", "filepath", "css/main.css")
+			assert.True(t, err == nil && u == "/v1/static/css/main.css")
+		}
+		{
+			u, err := router.URL("static", "filepath", "")
+			assert.True(t, err == nil && u == "/v1/static/")
+		}
+		{
+			u, err := router.URL("author", "id", "7")
+			assert.True(t, err == nil && u == "/v1/authors/7/")
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		{
+			_, err := router.URL("unknown")
+			assert.True(t, errors.Is(err, ErrRouteNotFound))
+		}
+		{
+			_, err := router.URL("bad")
+			assert.True(t, errors.Is(err, ErrRouteNotFound))
+		}
+		{
+			_, err := router.URL("work")
+			assert.True(t, errors.Is(err, ErrMissingParam))
+		}
+		{
+			_, err := router.URL("topic", "forum_id", "1", "topic_id")
+			assert.True(t, errors.Is(err, ErrOddParams))
+		}
+		{
+			_, err := router.URL("topic", "forum_id", "1", "topic", "2")
+			assert.True(t, errors.Is(err, ErrMissingParam))
+		}
+		{
+			_, err := router.URL("work", "id", "abc")
+			assert.True(t, errors.Is(err, ErrInvalidParam))
+		}
+		{
+			_, err := router.URL("topic", "forum_id", "1", "topic_id", "what?")
+			assert.True(t, errors.Is(err, ErrInvalidParam))
+		}
+		{
+			_, err := router.URL("static", "filepath", "a//b")
+			assert.True(t, errors.Is(err, ErrInvalidParam))
+		}
+	})
+}
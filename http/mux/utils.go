@@ -14,18 +14,34 @@ var httpMethods = map[string]bool{
 	http.MethodTrace:   true,
 }
 
-func walkGroup(g *Group, mws []Middleware, fn func(mws []Middleware, endpoint *Endpoint)) {
+type groupScope struct {
+	middlewares []Middleware
+	prefix      string
+	host        string
+	cors        *corsPolicy
+}
+
+func walkGroup(g *Group, scope groupScope, fn func(scope groupScope, endpoint *Endpoint)) {
 	if g == nil {
 		return
 	}
-	mws = append(mws, g.Middlewares...)
+	scope.middlewares = append(scope.middlewares, g.Middlewares...)
+	if g.Prefix != "" {
+		scope.prefix = joinPath(scope.prefix, g.Prefix)
+	}
+	if g.Host != "" {
+		scope.host = g.Host
+	}
+	if g.CORS != nil {
+		scope.cors = newCORSPolicy(g.CORS)
+	}
 	for _, endpoint := range g.Endpoints {
 		if endpoint != nil {
-			fn(mws, endpoint)
+			fn(scope, endpoint)
 		}
 	}
 	for _, sg := range g.Subgroups {
-		walkGroup(sg, mws, fn)
+		walkGroup(sg, scope, fn)
 	}
 }
 
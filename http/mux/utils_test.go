@@ -2,6 +2,7 @@ package mux
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/FantLab/go-kit/assert"
@@ -30,11 +31,47 @@ func Test_ChainHandler(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		assert.True(t, chainHandler(nil, nil) == nil)
 	})
+
+	t.Run("router", func(t *testing.T) {
+		var calls []string
+
+		makeMW := func(s string) Middleware {
+			return func(next http.Handler) http.Handler {
+				return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, s)
+					next.ServeHTTP(w, r)
+				})
+			}
+		}
+
+		g := new(Group)
+		g.Middleware(makeMW("group"))
+		g.Endpoint(http.MethodGet, "/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "handler")
+		})).WithMiddlewares(makeMW("endpoint 1"), makeMW("endpoint 2"))
+		g.Endpoint(http.MethodGet, "/x", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, "x")
+		}))
+
+		router, _ := NewRouter(&Config{
+			RootGroup:         g,
+			NotFoundHandler:   http.NotFoundHandler(),
+			GlobalMiddlewares: []Middleware{makeMW("global")},
+		})
+
+		router.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.DeepEqual(t, calls, []string{"global", "group", "endpoint 1", "endpoint 2", "handler"})
+
+		calls = nil
+
+		router.ServeHTTP(nil, httptest.NewRequest(http.MethodGet, "/x", nil))
+		assert.DeepEqual(t, calls, []string{"global", "group", "x"})
+	})
 }
 
 func Test_WalkGroup(t *testing.T) {
 	t.Run("nil", func(t *testing.T) {
-		walkGroup(nil, nil, nil)
+		walkGroup(nil, groupScope{}, nil)
 		assert.True(t, true)
 	})
 
@@ -63,20 +100,53 @@ func Test_WalkGroup(t *testing.T) {
 
 		var count int
 
-		walkGroup(g, nil, func(mws []Middleware, e *Endpoint) {
+		walkGroup(g, groupScope{}, func(scope groupScope, e *Endpoint) {
 			switch e.Path {
 			case "1":
-				assert.True(t, len(mws) == 1)
+				assert.True(t, len(scope.middlewares) == 1)
 			case "2":
-				assert.True(t, len(mws) == 2)
+				assert.True(t, len(scope.middlewares) == 2)
 			case "3":
-				assert.True(t, len(mws) == 3)
+				assert.True(t, len(scope.middlewares) == 3)
 			case "4":
-				assert.True(t, len(mws) == 4)
+				assert.True(t, len(scope.middlewares) == 4)
 			}
 			count++
 		})
 
 		assert.True(t, count == 4)
 	})
+
+	t.Run("prefix and host", func(t *testing.T) {
+		g := new(Group)
+
+		g.Endpoint("", "0", nil)
+
+		g.Subgroup(func(g *Group) {
+			g.Prefix = "/admin/v2/"
+			g.Host = "api.fantlab.ru"
+			g.Endpoint("", "1", nil)
+
+			g.Subgroup(func(g *Group) {
+				g.Prefix = "users"
+				g.Endpoint("", "2", nil)
+
+				g.Subgroup(func(g *Group) {
+					g.Host = "*.fantlab.ru"
+					g.Endpoint("", "3", nil)
+				})
+			})
+		})
+
+		scopes := make(map[string]groupScope)
+
+		walkGroup(g, groupScope{}, func(scope groupScope, e *Endpoint) {
+			scopes[e.Path] = scope
+		})
+
+		assert.True(t, scopes["0"].prefix == "" && scopes["0"].host == "")
+		assert.True(t, scopes["1"].prefix == "/admin/v2" && scopes["1"].host == "api.fantlab.ru")
+		assert.True(t, scopes["2"].prefix == "/admin/v2/users" && scopes["2"].host == "api.fantlab.ru")
+		assert.True(t, scopes["3"].prefix == "/admin/v2/users" && scopes["3"].host == "*.fantlab.ru")
+	})
 }
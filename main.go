@@ -1,10 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"io"
+	"os"
+
 	_ "github.com/FantLab/go-kit/anyserver"
 	_ "github.com/FantLab/go-kit/assert"
 	_ "github.com/FantLab/go-kit/codeflow"
-	_ "github.com/FantLab/go-kit/crypto/signed"
 	_ "github.com/FantLab/go-kit/database/rowscanner"
 	_ "github.com/FantLab/go-kit/database/sqlapi"
 	_ "github.com/FantLab/go-kit/database/sqlbuilder"
@@ -12,8 +15,57 @@ import (
 	_ "github.com/FantLab/go-kit/database/sqlstubs"
 	_ "github.com/FantLab/go-kit/env"
 	_ "github.com/FantLab/go-kit/http/mux"
+	_ "github.com/FantLab/go-kit/http/mux/auth"
+	_ "github.com/FantLab/go-kit/http/mux/csrf"
+	_ "github.com/FantLab/go-kit/http/mux/metrics"
+	_ "github.com/FantLab/go-kit/http/mux/middleware"
+	_ "github.com/FantLab/go-kit/http/mux/openapi"
+	_ "github.com/FantLab/go-kit/http/mux/presign"
+	_ "github.com/FantLab/go-kit/http/mux/ratelimit"
+	_ "github.com/FantLab/go-kit/http/mux/session"
 )
 
+const usage = `usage: go-kit <command> [flags]
+
+commands:
+  keygen   generate an ed25519 key pair
+  sign     sign a payload or a file
+  verify   verify a token or a detached signature
+  inspect  print a token without verifying it
+`
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"keygen":  keygen,
+	"sign":    sign,
+	"verify":  verify,
+	"inspect": inspect,
+}
+
 func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	cmd := commands[args[0]]
+
+	if cmd == nil {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err := cmd(args[1:], stdin, stdout, stderr); err != nil {
+		if err != errUsage {
+			fmt.Fprintf(stderr, "%s: %v\n", args[0], err)
+		}
+		return 1
+	}
 
+	return 0
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/FantLab/go-kit/assert"
+	"github.com/FantLab/go-kit/crypto/signed"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_CLI(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "cli")
+	defer os.RemoveAll(dir)
+
+	pub := filepath.Join(dir, "k.pub")
+	key := filepath.Join(dir, "k.key")
+
+	t.Run("usage", func(t *testing.T) {
+		code, _, stderr := runCommand("")
+		assert.True(t, code == 2 && strings.HasPrefix(stderr, "usage:"))
+
+		code, _, _ = runCommand("", "unknown")
+		assert.True(t, code == 2)
+
+		code, _, _ = runCommand("", "keygen", "-unknown")
+		assert.True(t, code == 1)
+	})
+
+	t.Run("keygen", func(t *testing.T) {
+		code, stdout, _ := runCommand("", "keygen", "-dir", dir, "-name", "k")
+		assert.True(t, code == 0 && stdout == pub+"\n"+key+"\n")
+
+		info, _ := os.Stat(key)
+		assert.True(t, info.Mode().Perm() == 0600)
+
+		_, err := signed.NewFileCoder64(pub, key)
+		assert.True(t, err == nil)
+
+		code, _, stderr := runCommand("", "keygen", "-dir", dir, "-name", "k")
+		assert.True(t, code == 1 && strings.Contains(stderr, "already exists"))
+
+		code, _, _ = runCommand("", "keygen", "-dir", dir, "-name", "k", "-force")
+		assert.True(t, code == 0)
+	})
+
+	var token string
+
+	t.Run("sign", func(t *testing.T) {
+		code, stdout, _ := runCommand(`{"uid":1}`, "sign", "-pub", pub, "-key", key)
+		assert.True(t, code == 0)
+		token = strings.TrimSpace(stdout)
+	})
+
+	t.Run("verify", func(t *testing.T) {
+		code, stdout, _ := runCommand(token, "verify", "-pub", pub)
+		assert.True(t, code == 0 && stdout == `{"uid":1}`)
+
+		tampered := []byte(token)
+		i := strings.LastIndexByte(token, '.') + 1
+		if tampered[i] == 'A' {
+			tampered[i] = 'B'
+		} else {
+			tampered[i] = 'A'
+		}
+
+		code, _, stderr := runCommand(string(tampered), "verify", "-pub", pub)
+		assert.True(t, code == 1 && strings.Contains(stderr, signed.ErrSign.Error()))
+	})
+
+	t.Run("keyring", func(t *testing.T) {
+		code, stdout, _ := runCommand("payload", "sign", "-keyring", dir)
+		assert.True(t, code == 0 && strings.HasPrefix(stdout, "k."))
+
+		code, stdout, _ = runCommand(stdout, "verify", "-keyring", dir)
+		assert.True(t, code == 0 && stdout == "payload")
+	})
+
+	t.Run("detached", func(t *testing.T) {
+		file := filepath.Join(dir, "dump.sql")
+		signature := filepath.Join(dir, "dump.sql.sig")
+		_ = ioutil.WriteFile(file, []byte("SELECT 1;"), 0644)
+
+		code, _, _ := runCommand("", "sign", "-pub", pub, "-key", key, "-in", file, "-detached", signature)
+		assert.True(t, code == 0)
+
+		code, stdout, _ := runCommand("", "verify", "-pub", pub, "-in", file, "-detached", signature)
+		assert.True(t, code == 0 && stdout == "OK\n")
+
+		code, _, _ = runCommand("SELECT 1;", "verify", "-pub", pub, "-detached", signature)
+		assert.True(t, code == 0)
+
+		stdinSignature := filepath.Join(dir, "stdin.sig")
+
+		code, _, _ = runCommand("SELECT 3;", "sign", "-pub", pub, "-key", key, "-detached", stdinSignature)
+		assert.True(t, code == 0)
+
+		code, stdout, _ = runCommand("SELECT 3;", "verify", "-pub", pub, "-detached", stdinSignature)
+		assert.True(t, code == 0 && stdout == "OK\n")
+
+		code, _, _ = runCommand("SELECT 4;", "verify", "-pub", pub, "-detached", stdinSignature)
+		assert.True(t, code == 1)
+
+		_ = ioutil.WriteFile(file, []byte("SELECT 2;"), 0644)
+
+		code, _, _ = runCommand("", "verify", "-pub", pub, "-in", file, "-detached", signature)
+		assert.True(t, code == 1)
+	})
+
+	t.Run("inspect", func(t *testing.T) {
+		code, stdout, _ := runCommand("", "inspect", token)
+		assert.True(t, code == 0 && stdout == "format: signed\npayload: {\"uid\":1}\n")
+
+		coder, _ := signed.NewFileCoder64(pub, key)
+
+		jwt, _ := coder.EncodeJWT(&signed.Claims{Subject: "1"})
+		code, stdout, _ = runCommand(string(jwt), "inspect")
+		assert.True(t, code == 0 && strings.HasPrefix(stdout, "format: jwt\nheader: {\"alg\":\"EdDSA\"") && strings.HasSuffix(stdout, "payload: {\"sub\":\"1\"}\n"))
+
+		secret, _ := signed.GenerateSecret()
+		sealer, _ := signed.NewSealedCoder(coder, secret)
+		sealed, _ := sealer.Encode([]byte("secret"))
+		code, stdout, _ = runCommand(string(sealed), "inspect")
+		assert.True(t, code == 0 && strings.HasPrefix(stdout, "format: sealed\n"))
+
+		code, _, _ = runCommand("garbage", "inspect")
+		assert.True(t, code == 1)
+	})
+}